@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/digitalocean/godo"
 )
@@ -16,6 +17,22 @@ const createDiskBackupsBasePath string = "settings/disks/%d/backups"
 const convertBackupToTemplateBasePath string = "backups/%d/convert"
 const deleteBackupsBasePath string = "backups"
 const backupNoteBasePath string = "backups/%d/note"
+const backupsBasePath string = "backups"
+const backupRestoreBasePath string = "backups/%d/restore"
+
+// Actions of the Backup transactions
+var (
+	backupRestoreActions = []string{"restore_backup", "restore_incremental_backup"}
+	backupConvertActions = []string{"convert_backup", "convert_incremental_backup"}
+)
+
+const (
+	// BackupTypeNormal is a type of the disk backup
+	BackupTypeNormal = "normal"
+
+	// BackupTypeIncremental is a type of the VirtualMachine incremental backup
+	BackupTypeIncremental = "incremental"
+)
 
 // BackupsService is an interface for interfacing with the Backup
 // endpoints of the OnApp API
@@ -31,7 +48,15 @@ type BackupsService interface {
 	AllComputeResourceBackups(context.Context, int) ([]Backup, *Response, error)
 	ListOfDiskBackups(context.Context, int, int) ([]Backup, *Response, error)
 	BackupNote(context.Context, int, *BackupNoteRequest) (*Response, error)
-	ConvertBackupToTemplate(context.Context, int, *ConvertBackupToTemplateRequest) (*Response, error)
+	ConvertBackupToTemplate(context.Context, int, *ConvertBackupToTemplateRequest) (*Transaction, *Response, error)
+	PublishAsTemplate(context.Context, int, *ConvertBackupToTemplateRequest, *BackupTemplateOptions) (*ImageTemplate, *Response, error)
+	Restore(context.Context, int, *BackupRestoreOptions) ([]Transaction, *Response, error)
+
+	CreateIncremental(context.Context, int, *IncrementalBackupCreateRequest) (*Backup, *Response, error)
+	ListChains(context.Context, int) ([]BackupChain, *Response, error)
+
+	PlanRetention(context.Context, int, int, *RetentionPolicy) (*RetentionPlan, *Response, error)
+	ExecuteRetention(context.Context, *RetentionPlan, *RetentionExecuteOptions) (*RetentionResult, error)
 }
 
 // BackupsServiceOp handles communication with the Backup related methods of the
@@ -139,9 +164,19 @@ func (s *BackupsServiceOp) Get(ctx context.Context, id int) (*Backup, *Response,
 		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
 	}
 
-	lst, resp, err := s.List(ctx, id, nil)
+	path := fmt.Sprintf("%s/%d%s", backupsBasePath, id, apiFormat)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return &lst[0], resp, err
+	root := new(backupRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Backup, resp, err
 }
 
 // Create Backup
@@ -171,12 +206,29 @@ func (s *BackupsServiceOp) Create(ctx context.Context, createRequest *BackupCrea
 	return root.Backup, resp, err
 }
 
-// Delete Backup
+// Delete Backup. Deletion of incremental backup which is not the latest one
+// in its chain is refused unless meta is *BackupDeleteOptions with Force.
+// Query parameters are taken from meta or from BackupDeleteOptions.Params.
 func (s *BackupsServiceOp) Delete(ctx context.Context, id int, meta interface{}) (*Response, error) {
 	if id < 1 {
 		return nil, godo.NewArgError("id", "cannot be less than 1")
 	}
 
+	opts, ok := meta.(*BackupDeleteOptions)
+	if ok {
+		meta = nil
+		if opts != nil {
+			meta = opts.Params
+		}
+	}
+
+	if opts == nil || !opts.Force {
+		resp, err := s.checkChain(ctx, id)
+		if err != nil {
+			return resp, err
+		}
+	}
+
 	path := fmt.Sprintf("%s/%d%s", deleteBackupsBasePath, id, apiFormat)
 
 	path, err := addOptions(path, meta)
@@ -247,19 +299,110 @@ func (s *BackupsServiceOp) BackupNote(ctx context.Context, id int, noteRequest *
 	return s.client.Do(ctx, req, nil)
 }
 
-// ConvertBackupToTemplate - Convert Backup to the Template
-func (s *BackupsServiceOp) ConvertBackupToTemplate(ctx context.Context, id int, convertRequest *ConvertBackupToTemplateRequest) (*Response, error) {
+// ConvertBackupToTemplate - Convert Backup to the Template and return transaction of converting
+func (s *BackupsServiceOp) ConvertBackupToTemplate(ctx context.Context, id int, convertRequest *ConvertBackupToTemplateRequest) (*Transaction, *Response, error) {
 	if id < 1 {
-		return nil, godo.NewArgError("id", "cannot be less than 1")
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
 	}
 
 	path := fmt.Sprintf(convertBackupToTemplateBasePath, id) + apiFormat
 
 	req, err := s.client.NewRequest(ctx, http.MethodPut, path, convertRequest)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	log.Println("Backup [ConvertBackupToTemplate]  req: ", req)
 
-	return s.client.Do(ctx, req, nil)
+	start := time.Now()
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return transactionSince(ctx, s.client, "Backup", id, serverTime(resp, start), backupConvertActions...)
+}
+
+// BackupRestoreOptions -
+type BackupRestoreOptions struct {
+	// Restore normal backup to the other Disk instead of the backed up one
+	DiskID int
+
+	// Restore incremental backup to the other VirtualMachine instead of the
+	// backed up one
+	VirtualMachineID int
+
+	// Wait for the restore transactions to be finished
+	Wait *TransactionWaitOptions
+}
+
+type backupRestoreRequest struct {
+	DiskID           int `json:"disk_id,omitempty"`
+	VirtualMachineID int `json:"virtual_machine_id,omitempty"`
+}
+
+type backupRestoreRequestRoot struct {
+	Backup *backupRestoreRequest `json:"backup"`
+}
+
+// Restore - Restore Backup and return chain of the restore transactions
+func (s *BackupsServiceOp) Restore(ctx context.Context, id int, opts *BackupRestoreOptions) ([]Transaction, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	if opts == nil {
+		opts = &BackupRestoreOptions{}
+	}
+
+	backup, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if !backup.Built {
+		return nil, resp, fmt.Errorf("Backup [%d] is not built yet", id)
+	}
+
+	if opts.DiskID > 0 && backup.BackupType == BackupTypeIncremental {
+		return nil, resp, godo.NewArgError("DiskID", "incremental backup can be restored only to the VirtualMachine")
+	}
+
+	if opts.VirtualMachineID > 0 && backup.BackupType != BackupTypeIncremental {
+		return nil, resp, godo.NewArgError("VirtualMachineID", "only incremental backup can be restored to the other VirtualMachine")
+	}
+
+	var rootRequest *backupRestoreRequestRoot
+	if opts.DiskID > 0 || opts.VirtualMachineID > 0 {
+		rootRequest = &backupRestoreRequestRoot{
+			Backup: &backupRestoreRequest{
+				DiskID:           opts.DiskID,
+				VirtualMachineID: opts.VirtualMachineID,
+			},
+		}
+	}
+
+	path := fmt.Sprintf(backupRestoreBasePath, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("Backup [Restore]  req: ", req)
+
+	start := time.Now()
+	resp, err = s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	root, resp, err := transactionSince(ctx, s.client, "Backup", id, serverTime(resp, start), backupRestoreActions...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	chain, resp, err := transactionChain(ctx, s.client, root)
+	if err != nil || opts.Wait == nil {
+		return chain, resp, err
+	}
+
+	return s.client.Transactions.Wait(ctx, chain, opts.Wait)
 }
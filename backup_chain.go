@@ -0,0 +1,261 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// IncrementalBackupCreateRequest - data for creating incremental backup of the VirtualMachine
+type IncrementalBackupCreateRequest struct {
+	BackupServerID     int    `json:"backup_server_id,omitempty"`
+	ForceWindowsBackup int    `json:"force_windows_backup,omitempty"`
+	Note               string `json:"note,omitempty"`
+}
+
+type incrementalBackupCreateRequestRoot struct {
+	IncrementalBackupCreateRequest *IncrementalBackupCreateRequest `json:"backup"`
+}
+
+// BackupDeleteOptions -
+type BackupDeleteOptions struct {
+	// Delete backup even if it breaks the chain of incremental backups
+	Force bool `url:"-"`
+
+	// Extra query parameters of the delete request, encoded as meta of Delete
+	Params interface{} `url:"-"`
+}
+
+// BackupChain represent base backup with all increments made over it.
+// Normal backup is a chain without increments.
+type BackupChain struct {
+	Base       Backup
+	Increments []Backup
+}
+
+// Incremental check if chain consists of incremental backups
+func (c *BackupChain) Incremental() bool {
+	return c.Base.BackupType == BackupTypeIncremental
+}
+
+// Backups return all backups of the chain, base backup is the first one
+func (c *BackupChain) Backups() []Backup {
+	return append([]Backup{c.Base}, c.Increments...)
+}
+
+// Last return the latest backup of the chain
+func (c *BackupChain) Last() *Backup {
+	if len(c.Increments) == 0 {
+		return &c.Base
+	}
+
+	return &c.Increments[len(c.Increments)-1]
+}
+
+// Contains check if backup belongs to the chain
+func (c *BackupChain) Contains(id int) bool {
+	for _, v := range c.Backups() {
+		if v.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanDelete check if backup can be deleted without breaking the chain, only
+// the latest backup of the chain can be deleted safely
+func (c *BackupChain) CanDelete(id int) bool {
+	return c.Last().ID == id
+}
+
+type backupChainKey struct {
+	targetType     string
+	targetID       int
+	backupServerID int
+}
+
+// BuildBackupChains group backups into chains. Incremental backups of the same
+// target stored on the same backup server form a single chain ordered by
+// creation time, every normal backup is a separate chain.
+func BuildBackupChains(backups []Backup) []BackupChain {
+	sorted := make([]Backup, len(backups))
+	copy(sorted, backups)
+	sortBackupsByCreatedAt(sorted)
+
+	var res []BackupChain
+	index := make(map[backupChainKey]int)
+
+	for _, v := range sorted {
+		if v.BackupType != BackupTypeIncremental {
+			res = append(res, BackupChain{Base: v})
+			continue
+		}
+
+		key := backupChainKey{
+			targetType:     v.TargetType,
+			targetID:       v.TargetID,
+			backupServerID: v.BackupServerID,
+		}
+
+		if i, ok := index[key]; ok {
+			res[i].Increments = append(res[i].Increments, v)
+			continue
+		}
+
+		index[key] = len(res)
+		res = append(res, BackupChain{Base: v})
+	}
+
+	return res
+}
+
+// sortBackupsByCreatedAt sort backups from the oldest to the newest one
+func sortBackupsByCreatedAt(backups []Backup) {
+	sort.SliceStable(backups, func(i, j int) bool {
+		ti, erri := time.Parse(time.RFC3339, backups[i].CreatedAt)
+		tj, errj := time.Parse(time.RFC3339, backups[j].CreatedAt)
+
+		if erri == nil && errj == nil && !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+
+		return backups[i].ID < backups[j].ID
+	})
+}
+
+// CreateIncremental - Create incremental backup of the VirtualMachine
+func (s *BackupsServiceOp) CreateIncremental(ctx context.Context, vmID int, createRequest *IncrementalBackupCreateRequest) (*Backup, *Response, error) {
+	if vmID < 1 {
+		return nil, nil, godo.NewArgError("vmID", "cannot be less than 1")
+	}
+
+	if createRequest == nil {
+		return nil, nil, godo.NewArgError("createRequest", "cannot be nil")
+	}
+
+	conf, resp, err := s.client.Configurations.Get(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if !conf.AllowIncrementalBackups {
+		return nil, resp, fmt.Errorf("incremental backups are not allowed by the Configuration")
+	}
+
+	vm, resp, err := s.client.VirtualMachines.Get(ctx, vmID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if !vm.SupportIncrementalBackups {
+		return nil, resp, fmt.Errorf("VirtualMachine [%d] does not support incremental backups", vmID)
+	}
+
+	path := fmt.Sprintf(listOfAllVSBackupsBasePath, vmID) + apiFormat
+	rootRequest := &incrementalBackupCreateRequestRoot{
+		IncrementalBackupCreateRequest: createRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("Backup [CreateIncremental]  req: ", req)
+
+	root := new(backupRoot)
+	resp, err = s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Backup, resp, err
+}
+
+// ListChains - List backups of the VirtualMachine grouped into chains
+func (s *BackupsServiceOp) ListChains(ctx context.Context, vmID int) ([]BackupChain, *Response, error) {
+	if vmID < 1 {
+		return nil, nil, godo.NewArgError("vmID", "cannot be less than 1")
+	}
+
+	lst, resp, err := s.listAll(ctx, vmID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return BuildBackupChains(lst), resp, err
+}
+
+// listAll - List backups of the VirtualMachine from all pages
+func (s *BackupsServiceOp) listAll(ctx context.Context, vmID int) ([]Backup, *Response, error) {
+	return s.listAllPages(ctx, fmt.Sprintf(listOfAllVSBackupsBasePath, vmID)+apiFormat)
+}
+
+// listAllOfDisk - List backups of the Disk of the VirtualMachine from all pages
+func (s *BackupsServiceOp) listAllOfDisk(ctx context.Context, vmID int, diskID int) ([]Backup, *Response, error) {
+	return s.listAllPages(ctx, fmt.Sprintf(listOfDiskBackupsBasePath, vmID, diskID)+apiFormat)
+}
+
+func (s *BackupsServiceOp) listAllPages(ctx context.Context, basePath string) ([]Backup, *Response, error) {
+	var res []Backup
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		path, err := addOptions(basePath, opt)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		var out []map[string]Backup
+		resp, err := s.client.Do(ctx, req, &out)
+		if err != nil {
+			return 0, resp, err
+		}
+
+		for i := range out {
+			res = append(res, out[i]["backup"])
+		}
+
+		return len(out), resp, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return res, resp, nil
+}
+
+// checkChain return error if deletion of the backup breaks the chain of
+// incremental backups
+func (s *BackupsServiceOp) checkChain(ctx context.Context, id int) (*Response, error) {
+	backup, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	if backup.BackupType != BackupTypeIncremental || backup.TargetType != "VirtualMachine" {
+		return resp, nil
+	}
+
+	chains, resp, err := s.ListChains(ctx, backup.TargetID)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, chain := range chains {
+		if chain.Contains(id) && !chain.CanDelete(id) {
+			return resp, fmt.Errorf("Backup [%d] is not the latest one in the chain of incremental backups, "+
+				"latest is [%d], use BackupDeleteOptions.Force to delete it anyway", id, chain.Last().ID)
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,319 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// Retention reasons
+const (
+	RetentionLast        = "last"
+	RetentionDaily       = "daily"
+	RetentionWeekly      = "weekly"
+	RetentionMonthly     = "monthly"
+	RetentionYearly      = "yearly"
+	RetentionChain       = "chain"
+	RetentionLocked      = "locked"
+	RetentionNotBuilt    = "not built"
+	RetentionUnknownTime = "unknown creation time"
+)
+
+// RetentionPolicy describe how many backups to keep, e.g. "7 daily, 4 weekly,
+// 12 monthly". The newest backup of every day (week, month, year) is kept
+// until the limit of the rule is reached. Backup is kept if any rule keeps it.
+type RetentionPolicy struct {
+	KeepLast    int
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+	KeepYearly  int
+
+	// Location used to split backups by days, UTC if nil
+	Location *time.Location
+}
+
+// RetentionDecision represent decision about single backup
+type RetentionDecision struct {
+	Backup  Backup
+	Keep    bool
+	Reasons []string
+}
+
+// RetentionPlan represent result of the RetentionPolicy evaluation
+type RetentionPlan struct {
+	Keep   []RetentionDecision
+	Delete []RetentionDecision
+}
+
+// RetentionExecuteOptions -
+type RetentionExecuteOptions struct {
+	// Only report backups which would be deleted
+	DryRun bool
+
+	// Number of backups deleted at the same time, 1 if zero
+	Concurrency int
+}
+
+// RetentionResult represent result of the RetentionPlan execution
+type RetentionResult struct {
+	DryRun  bool
+	Deleted []Backup
+	Failed  map[int]error
+}
+
+func (p RetentionPolicy) String() string {
+	return godo.Stringify(p)
+}
+
+// Validate check if policy keeps anything
+func (p RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepDaily < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 || p.KeepYearly < 0 {
+		return godo.NewArgError("RetentionPolicy", "limits cannot be less than 0")
+	}
+
+	if p.KeepLast+p.KeepDaily+p.KeepWeekly+p.KeepMonthly+p.KeepYearly == 0 {
+		return godo.NewArgError("RetentionPolicy", "at least one limit must be set, otherwise all backups are deleted")
+	}
+
+	return nil
+}
+
+type retentionRule struct {
+	reason string
+	limit  int
+	bucket func(time.Time) string
+}
+
+func (p RetentionPolicy) rules() []retentionRule {
+	return []retentionRule{
+		{RetentionLast, p.KeepLast, func(t time.Time) string { return t.Format(time.RFC3339Nano) }},
+		{RetentionDaily, p.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{RetentionWeekly, p.KeepWeekly, func(t time.Time) string {
+			y, w := t.ISOWeek()
+			return fmt.Sprintf("%d-%02d", y, w)
+		}},
+		{RetentionMonthly, p.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }},
+		{RetentionYearly, p.KeepYearly, func(t time.Time) string { return t.Format("2006") }},
+	}
+}
+
+// Evaluate decide which backups to keep and which to delete. Locked, not built
+// backups and backups with unknown creation time are always kept and not
+// counted by the rules, as well as all older backups of the incremental chain
+// of the kept backup.
+func (p RetentionPolicy) Evaluate(backups []Backup) (*RetentionPlan, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	sorted := make([]Backup, len(backups))
+	copy(sorted, backups)
+	sortBackupsByCreatedAt(sorted)
+
+	reasons := make(map[int][]string, len(sorted))
+	var dated []Backup
+	created := make(map[int]time.Time, len(sorted))
+
+	for _, v := range sorted {
+		t, err := time.Parse(time.RFC3339, v.CreatedAt)
+		switch {
+		case err != nil:
+			reasons[v.ID] = append(reasons[v.ID], RetentionUnknownTime)
+		case v.Locked:
+			reasons[v.ID] = append(reasons[v.ID], RetentionLocked)
+		case !v.Built:
+			reasons[v.ID] = append(reasons[v.ID], RetentionNotBuilt)
+		}
+
+		// kept anyway, so they do not take slots of the rules
+		if err == nil && !v.Locked && v.Built {
+			created[v.ID] = t.In(loc)
+			dated = append(dated, v)
+		}
+	}
+
+	// rules are applied from the newest backup to the oldest one
+	for _, rule := range p.rules() {
+		if rule.limit == 0 {
+			continue
+		}
+
+		count := 0
+		last := ""
+		for i := len(dated) - 1; i >= 0 && count < rule.limit; i-- {
+			b := dated[i].ID
+			key := rule.bucket(created[b])
+			if key == last {
+				continue
+			}
+
+			last = key
+			count++
+			reasons[b] = append(reasons[b], rule.reason)
+		}
+	}
+
+	// increments depend on all older backups of the chain
+	for _, chain := range BuildBackupChains(sorted) {
+		lst := chain.Backups()
+		keep := false
+		for i := len(lst) - 1; i >= 0; i-- {
+			if keep && len(reasons[lst[i].ID]) == 0 {
+				reasons[lst[i].ID] = []string{RetentionChain}
+			}
+			keep = keep || len(reasons[lst[i].ID]) > 0
+		}
+	}
+
+	plan := &RetentionPlan{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		v := sorted[i]
+		d := RetentionDecision{
+			Backup:  v,
+			Keep:    len(reasons[v.ID]) > 0,
+			Reasons: reasons[v.ID],
+		}
+
+		if d.Keep {
+			plan.Keep = append(plan.Keep, d)
+		} else {
+			plan.Delete = append(plan.Delete, d)
+		}
+	}
+
+	return plan, nil
+}
+
+// PlanRetention evaluate RetentionPolicy over all backups of the Disk, or of
+// the VirtualMachine if diskID is 0
+func (s *BackupsServiceOp) PlanRetention(ctx context.Context, vmID int, diskID int, policy *RetentionPolicy) (*RetentionPlan, *Response, error) {
+	if vmID < 1 {
+		return nil, nil, godo.NewArgError("vmID", "cannot be less than 1")
+	}
+
+	if policy == nil {
+		return nil, nil, godo.NewArgError("policy", "cannot be nil")
+	}
+
+	var lst []Backup
+	var resp *Response
+	var err error
+	if diskID > 0 {
+		lst, resp, err = s.listAllOfDisk(ctx, vmID, diskID)
+	} else {
+		lst, resp, err = s.listAll(ctx, vmID)
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	plan, err := policy.Evaluate(lst)
+
+	return plan, resp, err
+}
+
+// ExecuteRetention delete backups of the RetentionPlan. Backups of the same
+// incremental chain are deleted one by one from the newest, different chains
+// are processed concurrently.
+func (s *BackupsServiceOp) ExecuteRetention(ctx context.Context, plan *RetentionPlan, opts *RetentionExecuteOptions) (*RetentionResult, error) {
+	if plan == nil {
+		return nil, godo.NewArgError("plan", "cannot be nil")
+	}
+
+	if opts == nil {
+		opts = &RetentionExecuteOptions{}
+	}
+
+	res := &RetentionResult{
+		DryRun: opts.DryRun,
+		Failed: make(map[int]error),
+	}
+
+	var backups []Backup
+	for _, d := range plan.Delete {
+		if d.Keep {
+			continue
+		}
+		backups = append(backups, d.Backup)
+	}
+
+	chains := BuildBackupChains(backups)
+	if opts.DryRun {
+		for _, chain := range chains {
+			res.Deleted = append(res.Deleted, reverseBackups(chain.Backups())...)
+		}
+		return res, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, chain := range chains {
+		wg.Add(1)
+		go func(lst []Backup) {
+			defer wg.Done()
+
+			for _, v := range lst {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					mu.Lock()
+					res.Failed[v.ID] = ctx.Err()
+					mu.Unlock()
+					continue
+				}
+
+				_, err := s.Delete(ctx, v.ID, nil)
+				<-sem
+
+				mu.Lock()
+				if err != nil {
+					res.Failed[v.ID] = err
+				} else {
+					res.Deleted = append(res.Deleted, v)
+				}
+				mu.Unlock()
+
+				// older backups of the chain depend on this one
+				if err != nil {
+					break
+				}
+			}
+		}(reverseBackups(chain.Backups()))
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(res.Deleted, func(i, j int) bool { return res.Deleted[i].ID < res.Deleted[j].ID })
+
+	if len(res.Failed) > 0 {
+		return res, fmt.Errorf("failed to delete %d of %d backups", len(res.Failed), len(backups))
+	}
+
+	return res, nil
+}
+
+func reverseBackups(backups []Backup) []Backup {
+	res := make([]Backup, len(backups))
+	for i, v := range backups {
+		res[len(backups)-1-i] = v
+	}
+
+	return res
+}
@@ -0,0 +1,203 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func retentionIDs(lst []RetentionDecision) []int {
+	var res []int
+	for _, v := range lst {
+		res = append(res, v.Backup.ID)
+	}
+
+	return res
+}
+
+func TestRetentionPolicy_Evaluate(t *testing.T) {
+	backups := []Backup{
+		{ID: 1, Built: true, CreatedAt: "2021-01-15T10:00:00Z"},
+		{ID: 2, Built: true, CreatedAt: "2021-02-20T10:00:00Z"},
+		{ID: 3, Built: true, CreatedAt: "2021-03-01T10:00:00Z"},
+		{ID: 4, Built: true, CreatedAt: "2021-03-01T20:00:00Z"},
+		{ID: 5, Built: true, CreatedAt: "2021-03-02T10:00:00Z"},
+		{ID: 6, Built: true, CreatedAt: "2021-03-03T10:00:00Z"},
+		{ID: 7, Built: true, Locked: true, CreatedAt: "2020-12-01T10:00:00Z"},
+		{ID: 8, Built: false, CreatedAt: "2020-11-01T10:00:00Z"},
+		{ID: 9, Built: true, CreatedAt: "2020-10-01T10:00:00Z"},
+	}
+
+	policy := RetentionPolicy{KeepDaily: 2, KeepMonthly: 3}
+	plan, err := policy.Evaluate(backups)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{6, 5, 2, 1, 7, 8}, retentionIDs(plan.Keep))
+	require.Equal(t, []int{4, 3, 9}, retentionIDs(plan.Delete))
+
+	require.Equal(t, []string{RetentionDaily, RetentionMonthly}, plan.Keep[0].Reasons)
+	require.Equal(t, []string{RetentionMonthly}, plan.Keep[2].Reasons)
+	require.Equal(t, []string{RetentionNotBuilt}, plan.Keep[5].Reasons)
+}
+
+func TestRetentionPolicy_EvaluateKeepsChain(t *testing.T) {
+	backups := []Backup{
+		{ID: 1, Built: true, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, CreatedAt: "2021-03-01T10:00:00Z"},
+		{ID: 2, Built: true, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, CreatedAt: "2021-03-02T10:00:00Z"},
+		{ID: 3, Built: true, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, CreatedAt: "2021-03-03T10:00:00Z"},
+		{ID: 4, Built: true, CreatedAt: "2021-02-01T10:00:00Z"},
+	}
+
+	plan, err := RetentionPolicy{KeepLast: 1}.Evaluate(backups)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{3, 2, 1}, retentionIDs(plan.Keep))
+	require.Equal(t, []string{RetentionChain}, plan.Keep[2].Reasons)
+	require.Equal(t, []int{4}, retentionIDs(plan.Delete))
+}
+
+func TestRetentionPolicy_EvaluateSkipsNotBuilt(t *testing.T) {
+	backups := []Backup{
+		{ID: 1, Built: true, CreatedAt: "2021-03-01T10:00:00Z"},
+		{ID: 2, Built: false, CreatedAt: "2021-03-01T20:00:00Z"},
+		{ID: 3, Built: true, Locked: true, CreatedAt: "2021-03-01T21:00:00Z"},
+		{ID: 4, Built: true, CreatedAt: "2021-02-28T10:00:00Z"},
+		{ID: 5, Built: true, CreatedAt: "2021-02-27T10:00:00Z"},
+	}
+
+	plan, err := RetentionPolicy{KeepDaily: 2}.Evaluate(backups)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{3, 2, 1, 4}, retentionIDs(plan.Keep))
+	require.Equal(t, []string{RetentionDaily}, plan.Keep[2].Reasons)
+	require.Equal(t, []int{5}, retentionIDs(plan.Delete))
+}
+
+func TestBackups_PlanRetention(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var items []interface{}
+	for i := 1; i <= 150; i++ {
+		items = append(items, Backup{ID: i, Built: true, CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i).Format(time.RFC3339)})
+	}
+
+	mux.HandleFunc("/virtual_machines/1/backups.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "backup", items)
+	})
+
+	plan, _, err := client.Backups.PlanRetention(ctx, 1, 0, &RetentionPolicy{KeepDaily: 7})
+	require.NoError(t, err)
+	require.Len(t, plan.Keep, 7)
+	require.Equal(t, 150, plan.Keep[0].Backup.ID)
+	require.Len(t, plan.Delete, 143)
+}
+
+// setupRetentionDeletes serve normal backups and the chain 10 <- 11 of the
+// VirtualMachine 1, DELETE of the failed backups returns error
+func setupRetentionDeletes(t *testing.T, failed ...int) (*[]int, *sync.Mutex) {
+	var deleted []int
+	mu := &sync.Mutex{}
+
+	chain := []interface{}{
+		Backup{ID: 10, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, Built: true, CreatedAt: "2021-03-01T10:00:00Z"},
+		Backup{ID: 11, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, Built: true, CreatedAt: "2021-03-02T10:00:00Z"},
+	}
+
+	mux.HandleFunc("/virtual_machines/1/backups.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "backup", chain)
+	})
+
+	mux.HandleFunc("/backups/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/backups/"), "%d.json", &id)
+
+		if r.Method == http.MethodGet {
+			backup := Backup{ID: id, BackupType: BackupTypeNormal, TargetType: "Disk", TargetID: 5}
+			if id >= 10 {
+				backup = chain[id-10].(Backup)
+			}
+			json.NewEncoder(w).Encode(map[string]Backup{"backup": backup})
+			return
+		}
+
+		testMethod(t, r, http.MethodDelete)
+		for _, v := range failed {
+			if v == id {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"errors":["cannot delete"]}`)
+				return
+			}
+		}
+
+		mu.Lock()
+		deleted = append(deleted, id)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return &deleted, mu
+}
+
+func testRetentionPlan() *RetentionPlan {
+	plan := &RetentionPlan{}
+	for _, id := range []int{1, 2, 3, 4} {
+		plan.Delete = append(plan.Delete, RetentionDecision{Backup: Backup{ID: id, BackupType: BackupTypeNormal, CreatedAt: "2021-01-01T00:00:00Z"}})
+	}
+	plan.Delete = append(plan.Delete,
+		RetentionDecision{Backup: Backup{ID: 10, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, CreatedAt: "2021-03-01T10:00:00Z"}},
+		RetentionDecision{Backup: Backup{ID: 11, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, CreatedAt: "2021-03-02T10:00:00Z"}},
+	)
+
+	return plan
+}
+
+func TestBackups_ExecuteRetentionDryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted, _ := setupRetentionDeletes(t)
+
+	res, err := client.Backups.ExecuteRetention(ctx, testRetentionPlan(), &RetentionExecuteOptions{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, res.DryRun)
+	require.Len(t, res.Deleted, 6)
+	require.Empty(t, *deleted)
+}
+
+func TestBackups_ExecuteRetentionPartialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted, mu := setupRetentionDeletes(t, 3, 11)
+
+	res, err := client.Backups.ExecuteRetention(ctx, testRetentionPlan(), &RetentionExecuteOptions{Concurrency: 3})
+	require.Error(t, err)
+
+	var ids []int
+	for _, v := range res.Deleted {
+		ids = append(ids, v.ID)
+	}
+	require.Equal(t, []int{1, 2, 4}, ids)
+	require.Len(t, res.Failed, 2)
+	require.Contains(t, res.Failed, 3)
+	require.Contains(t, res.Failed, 11)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.ElementsMatch(t, []int{1, 2, 4}, *deleted, "older backup of the chain is not deleted after failure")
+}
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	_, err := RetentionPolicy{}.Evaluate(nil)
+	require.Error(t, err)
+
+	_, err = RetentionPolicy{KeepDaily: -1, KeepWeekly: 2}.Evaluate(nil)
+	require.Error(t, err)
+}
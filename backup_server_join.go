@@ -10,8 +10,8 @@ import (
 )
 
 var backupServerJoinPaths = map[string]string{
-	"Hypervisor":      "settings/hypervisors/%d/backup_server_joins",
-	"HypervisorGroup": "settings/hypervisor_zones/%d/backup_server_joins",
+	JoinTargetHypervisor:      "settings/hypervisors/%d/backup_server_joins",
+	JoinTargetHypervisorGroup: "settings/hypervisor_zones/%d/backup_server_joins",
 }
 
 // BackupServerJoinsService is an interface for interfacing with the BackupServerJoin
@@ -20,6 +20,8 @@ type BackupServerJoinsService interface {
 	Get(context.Context, string, int, int) (*BackupServerJoin, *Response, error)
 	Create(context.Context, *BackupServerJoinCreateRequest) (*BackupServerJoin, *Response, error)
 	Delete(context.Context, *BackupServerJoinDeleteRequest, interface{}) (*Response, error)
+	ListByTarget(context.Context, JoinTarget, *ListOptions) ([]BackupServerJoin, *Response, error)
+	Sync(context.Context, JoinTarget, []int, *JoinSyncOptions) (*JoinSyncReport, *Response, error)
 }
 
 // BackupServerJoinsServiceOp -
@@ -189,3 +191,50 @@ func (s *BackupServerJoinsServiceOp) Delete(ctx context.Context, deleteRequest *
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// ListByTarget - List BackupServerJoins of the Hypervisor or HypervisorGroup
+func (s *BackupServerJoinsServiceOp) ListByTarget(ctx context.Context, target JoinTarget, opt *ListOptions) ([]BackupServerJoin, *Response, error) {
+	if err := target.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.List(ctx, &BackupServerJoinCreateRequest{TargetJoinType: target.Type, TargetJoinID: target.ID}, opt)
+}
+
+// Sync BackupServerJoins of the target with desired BackupServer IDs
+func (s *BackupServerJoinsServiceOp) Sync(ctx context.Context, target JoinTarget, backupServerIDs []int, opts *JoinSyncOptions) (*JoinSyncReport, *Response, error) {
+	var joins []BackupServerJoin
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.ListByTarget(ctx, target, opt)
+		joins = append(joins, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	current := make([]joinItem, len(joins))
+	for i, v := range joins {
+		current[i] = joinItem{joinID: v.ID, resourceID: v.BackupServerID}
+	}
+
+	desired := make([]joinItem, len(backupServerIDs))
+	for i, id := range backupServerIDs {
+		desired[i] = joinItem{resourceID: id}
+	}
+
+	remove := func(v joinItem) (*Response, error) {
+		return s.Delete(ctx, &BackupServerJoinDeleteRequest{ID: v.joinID, TargetJoinType: target.Type, TargetJoinID: target.ID}, nil)
+	}
+
+	add := func(v joinItem) (*Response, error) {
+		_, resp, err := s.Create(ctx, &BackupServerJoinCreateRequest{
+			BackupServerID: v.resourceID,
+			TargetJoinType: target.Type,
+			TargetJoinID:   target.ID,
+		})
+		return resp, err
+	}
+
+	return syncJoins(target, current, desired, opts, remove, add)
+}
@@ -0,0 +1,152 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// BackupTemplateOptions -
+type BackupTemplateOptions struct {
+	// Attach template to the ImageTemplateGroup if set
+	ImageTemplateGroupID int
+
+	// Minimal disk size in GB and memory size in MB of the template, not
+	// changed if zero
+	MinDiskSize   int
+	MinMemorySize int
+
+	// Options of the waiting for the converting transaction and for the
+	// template to become active
+	Wait *TransactionWaitOptions
+}
+
+// PublishAsTemplate convert Backup to the ImageTemplate, wait until the
+// template is active and optionally attach it to the ImageTemplateGroup and
+// set its minimal disk and memory sizes.
+func (s *BackupsServiceOp) PublishAsTemplate(ctx context.Context, id int, convertRequest *ConvertBackupToTemplateRequest, opts *BackupTemplateOptions) (*ImageTemplate, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	if convertRequest == nil {
+		return nil, nil, godo.NewArgError("convertRequest", "cannot be nil")
+	}
+
+	if opts == nil {
+		opts = &BackupTemplateOptions{}
+	}
+
+	wait := opts.Wait
+	if wait == nil {
+		wait = &TransactionWaitOptions{}
+	}
+
+	// remember existing templates to find the new one after converting
+	before, resp, err := s.listAllImageTemplates(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	existing := make(map[int]bool, len(before))
+	for _, v := range before {
+		existing[v.ID] = true
+	}
+
+	trx, resp, err := s.ConvertBackupToTemplate(ctx, id, convertRequest)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	_, resp, err = s.client.Transactions.Wait(ctx, []Transaction{*trx}, wait)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	tpl, resp, err := s.waitForTemplate(ctx, existing, convertRequest.Label, wait.Interval)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if opts.MinDiskSize > 0 || opts.MinMemorySize > 0 {
+		editRequest := &ImageTemplateEditRequest{
+			MinDiskSize:       opts.MinDiskSize,
+			MinMemorySize:     opts.MinMemorySize,
+			AllowedHotMigrate: tpl.AllowedHotMigrate,
+		}
+
+		resp, err = s.client.ImageTemplates.Edit(ctx, tpl.ID, editRequest)
+		if err != nil {
+			return tpl, resp, err
+		}
+
+		if opts.MinDiskSize > 0 {
+			tpl.MinDiskSize = opts.MinDiskSize
+		}
+		if opts.MinMemorySize > 0 {
+			tpl.MinMemorySize = opts.MinMemorySize
+		}
+	}
+
+	if opts.ImageTemplateGroupID > 0 {
+		attachRequest := &ImageTemplateGroupAttachRequest{
+			TemplateID: tpl.ID,
+		}
+
+		_, resp, err = s.client.ImageTemplateGroups.Attach(ctx, opts.ImageTemplateGroupID, attachRequest)
+		if err != nil {
+			return tpl, resp, err
+		}
+	}
+
+	return tpl, resp, nil
+}
+
+// waitForTemplate poll ImageTemplates until the new template with the label
+// is active
+func (s *BackupsServiceOp) waitForTemplate(ctx context.Context, existing map[int]bool, label string, interval time.Duration) (*ImageTemplate, *Response, error) {
+	if interval <= 0 {
+		interval = defaultTransactionWaitInterval
+	}
+
+	for {
+		lst, resp, err := s.listAllImageTemplates(ctx)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for i := range lst {
+			v := lst[i]
+			if existing[v.ID] || (label != "" && v.Label != label) {
+				continue
+			}
+
+			if v.State == ImageTemplateActive {
+				return &v, resp, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, resp, fmt.Errorf("ImageTemplate converted from Backup is not active: %s", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// listAllImageTemplates - List ImageTemplates from all pages
+func (s *BackupsServiceOp) listAllImageTemplates(ctx context.Context) ([]ImageTemplate, *Response, error) {
+	var res []ImageTemplate
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.ImageTemplates.List(ctx, opt)
+		res = append(res, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return res, resp, nil
+}
@@ -0,0 +1,136 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupConvertTransactions serve converting transaction 11 of the Backup 7
+// created now and pending, and the older failed one
+func setupConvertTransactions(t *testing.T) {
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]Transaction{
+			{"transaction": {ID: 11, Action: "convert_backup", AssociatedObjectType: "Backup", AssociatedObjectID: 7, CreatedAt: time.Now().UTC().Format(time.RFC3339), Status: TransactionPending}},
+			{"transaction": {ID: 5, Action: "convert_backup", AssociatedObjectType: "Backup", AssociatedObjectID: 7, CreatedAt: "2020-01-01T00:00:00Z", Status: TransactionFailed}},
+		})
+	})
+
+	mux.HandleFunc("/transactions/11.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		json.NewEncoder(w).Encode(map[string]Transaction{"transaction": {ID: 11, Action: "convert_backup", Status: TransactionComplete}})
+	})
+}
+
+func TestBackups_PublishAsTemplate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	lists := 0
+	mux.HandleFunc("/templates.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		lists++
+		switch lists {
+		case 1:
+			fmt.Fprint(w, `[{"image_template":{"id":1,"label":"tpl","state":"active"}}]`)
+		case 2:
+			fmt.Fprint(w, `[{"image_template":{"id":1,"label":"tpl","state":"active"}},{"image_template":{"id":2,"label":"tpl","state":"inactive"}}]`)
+		default:
+			fmt.Fprint(w, `[{"image_template":{"id":1,"label":"tpl","state":"active"}},{"image_template":{"id":2,"label":"tpl","state":"active"}}]`)
+		}
+	})
+
+	mux.HandleFunc("/backups/7/convert.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+	})
+
+	setupConvertTransactions(t)
+
+	var edit map[string]ImageTemplateEditRequest
+	mux.HandleFunc("/templates/2.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&edit))
+	})
+
+	attached := false
+	mux.HandleFunc("/settings/image_template_groups/3/relation_group_templates", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		attached = true
+		fmt.Fprint(w, `{"image_template_group":{"id":3}}`)
+	})
+
+	opts := &BackupTemplateOptions{
+		ImageTemplateGroupID: 3,
+		MinDiskSize:          10,
+		MinMemorySize:        512,
+		Wait:                 &TransactionWaitOptions{Interval: time.Millisecond},
+	}
+
+	tpl, _, err := client.Backups.PublishAsTemplate(ctx, 7, &ConvertBackupToTemplateRequest{Label: "tpl"}, opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, tpl.ID)
+	require.Equal(t, 10, tpl.MinDiskSize)
+	require.Equal(t, 3, lists)
+	require.Equal(t, 512, edit["image_template"].MinMemorySize)
+	require.True(t, attached)
+}
+
+func TestBackups_PublishAsTemplateLargeCatalogue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var items []interface{}
+	for i := 1; i <= 150; i++ {
+		items = append(items, ImageTemplate{ID: i, Label: "tpl", State: ImageTemplateActive})
+	}
+
+	mux.HandleFunc("/templates.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "image_template", items)
+	})
+
+	mux.HandleFunc("/backups/7/convert.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		items = append(items, ImageTemplate{ID: 151, Label: "tpl", State: ImageTemplateActive})
+	})
+
+	setupConvertTransactions(t)
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	tpl, _, err := client.Backups.PublishAsTemplate(waitCtx, 7, &ConvertBackupToTemplateRequest{Label: "tpl"},
+		&BackupTemplateOptions{Wait: &TransactionWaitOptions{Interval: time.Millisecond}})
+	require.NoError(t, err)
+	require.Equal(t, 151, tpl.ID)
+}
+
+func TestBackups_ConvertBackupToTemplate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/backups/7/convert.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+	})
+
+	mux.HandleFunc("/backups/8/convert.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+	})
+
+	setupConvertTransactions(t)
+
+	trx, _, err := client.Backups.ConvertBackupToTemplate(ctx, 7, &ConvertBackupToTemplateRequest{Label: "tpl"})
+	require.NoError(t, err)
+	require.Equal(t, 11, trx.ID)
+	require.True(t, trx.Pending())
+
+	// there is no converting transaction of the Backup 8
+	trx, _, err = client.Backups.ConvertBackupToTemplate(ctx, 8, &ConvertBackupToTemplateRequest{Label: "tpl"})
+	require.Error(t, err)
+	require.Nil(t, trx)
+}
@@ -0,0 +1,228 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackups_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/backups/7.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"backup":{"id":7,"disk_id":3,"backup_type":"normal","built":true,"target_id":3,"target_type":"Disk"}}`)
+	})
+
+	got, _, err := client.Backups.Get(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, &Backup{ID: 7, DiskID: 3, BackupType: BackupTypeNormal, Built: true, TargetID: 3, TargetType: "Disk"}, got)
+}
+
+func TestBackups_GetNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/backups/7.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":{"base":["Backup not found"]}}`)
+	})
+
+	got, _, err := client.Backups.Get(ctx, 7)
+	require.Error(t, err)
+	require.Nil(t, got)
+}
+
+func TestBuildBackupChains(t *testing.T) {
+	backups := []Backup{
+		{ID: 4, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 1, CreatedAt: "2021-03-03T00:00:00Z"},
+		{ID: 2, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 1, CreatedAt: "2021-03-01T00:00:00Z"},
+		{ID: 5, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 2, CreatedAt: "2021-03-02T00:00:00Z"},
+		{ID: 3, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 1, CreatedAt: "2021-03-02T00:00:00Z"},
+		{ID: 1, BackupType: BackupTypeNormal, TargetType: "Disk", TargetID: 10, CreatedAt: "2021-02-01T00:00:00Z"},
+	}
+
+	chains := BuildBackupChains(backups)
+	require.Len(t, chains, 3)
+
+	require.False(t, chains[0].Incremental())
+	require.Equal(t, 1, chains[0].Base.ID)
+	require.True(t, chains[0].CanDelete(1))
+
+	require.True(t, chains[1].Incremental())
+	require.Equal(t, 2, chains[1].Base.ID)
+	require.Equal(t, []int{3, 4}, []int{chains[1].Increments[0].ID, chains[1].Increments[1].ID})
+	require.False(t, chains[1].CanDelete(2))
+	require.False(t, chains[1].CanDelete(3))
+	require.True(t, chains[1].CanDelete(4))
+
+	require.Equal(t, 5, chains[2].Base.ID)
+	require.Empty(t, chains[2].Increments)
+}
+
+// setupBackupChain serve 100 normal backups of the Disk on the first page and
+// chain of incremental backups 200 <- 201 of the VirtualMachine 1 split
+// between the first and the second pages, return queries of DELETE requests
+func setupBackupChain(t *testing.T) *[]string {
+	var items []interface{}
+	for i := 1; i < allPagesPerPage; i++ {
+		items = append(items, Backup{ID: i, BackupType: BackupTypeNormal, TargetType: "Disk", TargetID: 10, CreatedAt: "2021-01-01T00:00:00Z"})
+	}
+	items = append(items,
+		Backup{ID: 200, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 1, CreatedAt: "2021-03-01T00:00:00Z"},
+		Backup{ID: 201, BackupType: BackupTypeIncremental, TargetType: "VirtualMachine", TargetID: 1, BackupServerID: 1, CreatedAt: "2021-03-02T00:00:00Z"},
+	)
+
+	mux.HandleFunc("/virtual_machines/1/backups.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "backup", items)
+	})
+
+	var deleted []string
+	mux.HandleFunc("/backups/200.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.RawQuery)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"backup":{"id":200,"backup_type":"incremental","target_id":1,"target_type":"VirtualMachine","backup_server_id":1}}`)
+	})
+
+	return &deleted
+}
+
+func TestBackups_ListChains(t *testing.T) {
+	setup()
+	defer teardown()
+
+	setupBackupChain(t)
+
+	chains, _, err := client.Backups.ListChains(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, chains, allPagesPerPage)
+
+	last := chains[len(chains)-1]
+	require.Equal(t, 200, last.Base.ID)
+	require.Len(t, last.Increments, 1)
+}
+
+func TestBackups_DeleteChainBaseRefused(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted := setupBackupChain(t)
+
+	_, err := client.Backups.Delete(ctx, 200, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "latest is [201]")
+
+	_, err = client.Backups.Delete(ctx, 200, &BackupDeleteOptions{})
+	require.Error(t, err)
+	require.Empty(t, *deleted)
+}
+
+func TestBackups_DeleteForce(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted := setupBackupChain(t)
+
+	params := struct {
+		Reason string `url:"reason"`
+	}{"cleanup"}
+
+	_, err := client.Backups.Delete(ctx, 200, &BackupDeleteOptions{Force: true, Params: params})
+	require.NoError(t, err)
+	require.Equal(t, []string{"reason=cleanup"}, *deleted)
+}
+
+func TestBackups_Restore(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/backups/7.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"backup":{"id":7,"built":true,"backup_type":"normal","target_id":10,"target_type":"Disk"}}`)
+	})
+
+	var bodies []string
+	mux.HandleFunc("/backups/7/restore.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// chain 5 of the previous restore of the same Backup and the new chain 6
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().UTC().Format(time.RFC3339)
+		old := "2020-01-01T00:00:00Z"
+		json.NewEncoder(w).Encode([]map[string]Transaction{
+			{"transaction": {ID: 22, ChainID: 6, Action: "start_virtual_machine", AssociatedObjectType: "VirtualMachine", AssociatedObjectID: 1, CreatedAt: now, Status: TransactionPending}},
+			{"transaction": {ID: 21, ChainID: 6, Action: "stop_virtual_machine", AssociatedObjectType: "VirtualMachine", AssociatedObjectID: 1, CreatedAt: now, Status: TransactionPending}},
+			{"transaction": {ID: 20, ChainID: 6, Action: "restore_backup", AssociatedObjectType: "Backup", AssociatedObjectID: 7, CreatedAt: now, Status: TransactionPending}},
+			{"transaction": {ID: 11, ChainID: 5, Action: "start_virtual_machine", AssociatedObjectType: "VirtualMachine", AssociatedObjectID: 1, CreatedAt: old, Status: TransactionComplete}},
+			{"transaction": {ID: 10, ChainID: 5, Action: "restore_backup", AssociatedObjectType: "Backup", AssociatedObjectID: 7, CreatedAt: old, Status: TransactionComplete}},
+		})
+	})
+
+	for _, id := range []int{20, 21, 22} {
+		id := id
+		mux.HandleFunc(fmt.Sprintf("/transactions/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]Transaction{"transaction": {ID: id, ChainID: 6, Status: TransactionComplete}})
+		})
+	}
+
+	chain, _, err := client.Backups.Restore(ctx, 7, nil)
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	for i, id := range []int{20, 21, 22} {
+		require.Equal(t, id, chain[i].ID)
+		require.True(t, chain[i].Pending())
+	}
+
+	chain, _, err = client.Backups.Restore(ctx, 7, &BackupRestoreOptions{DiskID: 11, Wait: &TransactionWaitOptions{Interval: time.Millisecond}})
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	for _, trx := range chain {
+		require.True(t, trx.Complete())
+	}
+
+	_, _, err = client.Backups.Restore(ctx, 7, &BackupRestoreOptions{VirtualMachineID: 1})
+	require.Error(t, err)
+
+	require.Len(t, bodies, 2)
+	require.JSONEq(t, `null`, bodies[0])
+	require.JSONEq(t, `{"backup":{"disk_id":11}}`, bodies[1])
+}
+
+func TestBackups_RestoreNoTransaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/backups/7.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"backup":{"id":7,"built":true,"backup_type":"normal"}}`)
+	})
+
+	mux.HandleFunc("/backups/7/restore.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]Transaction{
+			{"transaction": {ID: 10, ChainID: 5, Action: "restore_backup", AssociatedObjectType: "Backup", AssociatedObjectID: 7, CreatedAt: "2020-01-01T00:00:00Z"}},
+		})
+	})
+
+	chain, _, err := client.Backups.Restore(ctx, 7, nil)
+	require.Error(t, err)
+	require.Nil(t, chain)
+}
@@ -0,0 +1,451 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// Capacity resources
+const (
+	CapacityMemory    = "memory"
+	CapacityCPU       = "cpu"
+	CapacityDisk      = "disk"
+	CapacityIPAddress = "ip_address"
+)
+
+// Capacity placement strategies
+const (
+	// Fill the most loaded Hypervisor and DataStore first (best fit)
+	CapacityPackDense = "dense"
+
+	// Place to the least loaded Hypervisor and DataStore first (worst fit)
+	CapacitySpread = "spread"
+)
+
+// CapacityPlanOptions -
+type CapacityPlanOptions struct {
+	// CapacityPackDense or CapacitySpread, CapacityPackDense if empty
+	Strategy string
+
+	// Number of vCPUs allowed per CPU of the Hypervisor, 1 if zero
+	CPUOvercommit float64
+
+	// Memory in MB kept free on every Hypervisor
+	MemoryReserve int
+
+	// Number of IP addresses assigned to every VirtualMachine, 1 if zero
+	IPAddressesPerVM int
+}
+
+// CapacityResources - resources of the HypervisorGroup used for planning.
+// Hypervisors which are offline, disabled, in maintenance mode or spare are
+// not used for placement.
+type CapacityResources struct {
+	Hypervisors []Hypervisor
+
+	// vCPUs of VirtualMachines placed on the Hypervisor by its ID
+	UsedCpus map[int]int
+
+	DataStores      []DataStore
+	FreeIPAddresses int
+}
+
+// CapacityHeadroom - total amount of the resource, amount required for the
+// requested VirtualMachines and amount left after their placement. Memory is
+// in MB, disk in GB, CPU in vCPUs.
+type CapacityHeadroom struct {
+	Resource  string
+	Available int
+	Required  int
+	Left      int
+}
+
+// CapacityPlacement - suggested placement of the single VirtualMachine
+type CapacityPlacement struct {
+	HypervisorID int
+	DataStoreID  int
+}
+
+// CapacityPlan represent result of the placement simulation
+type CapacityPlan struct {
+	Requested int
+
+	// Number of VirtualMachines which fit into the resources
+	MaxVirtualMachines int
+
+	Headroom []CapacityHeadroom
+
+	// Resource which runs out first
+	Bottleneck string
+
+	// Placements of the requested VirtualMachines, only placed ones if the
+	// requested number does not fit
+	Placements []CapacityPlacement
+}
+
+type capacityHypervisor struct {
+	id      int
+	freeMem int
+	freeCPU int
+}
+
+type capacityDataStore struct {
+	id       int
+	freeDisk int
+}
+
+func (o CapacityPlanOptions) String() string {
+	return godo.Stringify(o)
+}
+
+// Fits check if all requested VirtualMachines can be placed
+func (p *CapacityPlan) Fits() bool {
+	return p.MaxVirtualMachines >= p.Requested
+}
+
+func (p *CapacityPlan) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d of %d VirtualMachines fit, %d at most, %s runs out first\n",
+		len(p.Placements), p.Requested, p.MaxVirtualMachines, p.Bottleneck)
+	for _, v := range p.Headroom {
+		fmt.Fprintf(&b, "  %-10s available %d, required %d, left %d\n", v.Resource, v.Available, v.Required, v.Left)
+	}
+
+	return b.String()
+}
+
+// SimulateCapacity place VirtualMachines of the InstancePackage one by one to
+// the resources until any of them runs out. Every VirtualMachine takes memory
+// and CPUs of the single Hypervisor, disk of the single DataStore and IP
+// addresses.
+func SimulateCapacity(res *CapacityResources, pkg *InstancePackage, count int, opts *CapacityPlanOptions) (*CapacityPlan, error) {
+	if res == nil || pkg == nil {
+		return nil, godo.NewArgError("res || pkg", "cannot be nil")
+	}
+
+	if pkg.Memory < 1 || pkg.Cpus < 1 || pkg.DiskSize < 1 {
+		return nil, godo.NewArgError("InstancePackage", "Memory, Cpus and DiskSize cannot be less than 1")
+	}
+
+	if count < 0 {
+		return nil, godo.NewArgError("count", "cannot be less than 0")
+	}
+
+	if opts == nil {
+		opts = &CapacityPlanOptions{}
+	}
+
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = CapacityPackDense
+	}
+	if strategy != CapacityPackDense && strategy != CapacitySpread {
+		return nil, godo.NewArgError("Strategy", fmt.Sprintf("%q is not supported, use %q or %q", strategy, CapacityPackDense, CapacitySpread))
+	}
+
+	overcommit := opts.CPUOvercommit
+	if overcommit <= 0 {
+		overcommit = 1
+	}
+
+	ipsPerVM := opts.IPAddressesPerVM
+	if ipsPerVM < 1 {
+		ipsPerVM = 1
+	}
+
+	var hvs []*capacityHypervisor
+	for _, hv := range res.Hypervisors {
+		if !hv.Online || !hv.Enabled || hv.MaintenanceMode || hv.Spare {
+			continue
+		}
+
+		hvs = append(hvs, &capacityHypervisor{
+			id:      hv.ID,
+			freeMem: capacityFreeMemory(&hv) - opts.MemoryReserve,
+			freeCPU: int(float64(hv.TotalCpus)*overcommit) - res.UsedCpus[hv.ID],
+		})
+	}
+
+	var dss []*capacityDataStore
+	for _, ds := range res.DataStores {
+		if !ds.Enabled {
+			continue
+		}
+
+		dss = append(dss, &capacityDataStore{id: ds.ID, freeDisk: ds.DataStoreSize - ds.Usage})
+	}
+
+	plan := &CapacityPlan{
+		Requested: count,
+		Headroom: []CapacityHeadroom{
+			{Resource: CapacityMemory, Required: count * pkg.Memory},
+			{Resource: CapacityCPU, Required: count * pkg.Cpus},
+			{Resource: CapacityDisk, Required: count * pkg.DiskSize},
+			{Resource: CapacityIPAddress, Available: res.FreeIPAddresses, Required: count * ipsPerVM},
+		},
+	}
+
+	for _, v := range hvs {
+		plan.Headroom[0].Available += capacityPositive(v.freeMem)
+		plan.Headroom[1].Available += capacityPositive(v.freeCPU)
+	}
+	for _, v := range dss {
+		plan.Headroom[2].Available += capacityPositive(v.freeDisk)
+	}
+	for i := range plan.Headroom {
+		plan.Headroom[i].Left = plan.Headroom[i].Available - plan.Headroom[i].Required
+	}
+
+	freeIPs := res.FreeIPAddresses
+	for {
+		if freeIPs < ipsPerVM {
+			plan.Bottleneck = CapacityIPAddress
+			break
+		}
+
+		ds := pickCapacityDataStore(dss, pkg.DiskSize, strategy)
+		if ds == nil {
+			plan.Bottleneck = CapacityDisk
+			break
+		}
+
+		hv, bottleneck := pickCapacityHypervisor(hvs, pkg, strategy)
+		if hv == nil {
+			plan.Bottleneck = bottleneck
+			break
+		}
+
+		freeIPs -= ipsPerVM
+		ds.freeDisk -= pkg.DiskSize
+		hv.freeMem -= pkg.Memory
+		hv.freeCPU -= pkg.Cpus
+
+		plan.MaxVirtualMachines++
+		if len(plan.Placements) < count {
+			plan.Placements = append(plan.Placements, CapacityPlacement{HypervisorID: hv.id, DataStoreID: ds.id})
+		}
+	}
+
+	return plan, nil
+}
+
+// capacityFreeMemory return free memory of the Hypervisor, but not more than
+// total memory left after Dom0 and VirtualMachines
+func capacityFreeMemory(hv *Hypervisor) int {
+	free := hv.FreeMemory
+	if hv.TotalMemory == 0 {
+		return free
+	}
+
+	left := hv.TotalMemory - hv.Dom0MemorySize - hv.TotalMemoryAllocatedByVms
+	if free == 0 || left < free {
+		return left
+	}
+
+	return free
+}
+
+func capacityPositive(v int) int {
+	if v < 0 {
+		return 0
+	}
+
+	return v
+}
+
+// capacityBetter check if free amount a is preferred over b by the strategy
+func capacityBetter(a int, b int, strategy string) bool {
+	if strategy == CapacitySpread {
+		return a > b
+	}
+
+	return a < b
+}
+
+func pickCapacityDataStore(dss []*capacityDataStore, size int, strategy string) *capacityDataStore {
+	var res *capacityDataStore
+	for _, v := range dss {
+		if v.freeDisk >= size && (res == nil || capacityBetter(v.freeDisk, res.freeDisk, strategy)) {
+			res = v
+		}
+	}
+
+	return res
+}
+
+// pickCapacityHypervisor return Hypervisor for the VirtualMachine or resource
+// which is missing on all Hypervisors
+func pickCapacityHypervisor(hvs []*capacityHypervisor, pkg *InstancePackage, strategy string) (*capacityHypervisor, string) {
+	var res *capacityHypervisor
+	memory := false
+
+	for _, v := range hvs {
+		if v.freeMem < pkg.Memory {
+			continue
+		}
+		memory = true
+
+		if v.freeCPU < pkg.Cpus {
+			continue
+		}
+
+		if res == nil || capacityBetter(v.freeMem, res.freeMem, strategy) {
+			res = v
+		}
+	}
+
+	if res == nil && !memory {
+		return nil, CapacityMemory
+	}
+	if res == nil {
+		return nil, CapacityCPU
+	}
+
+	return res, ""
+}
+
+// PlanCapacity check if count VirtualMachines of the InstancePackage fit into
+// the HypervisorGroup. Hypervisors, DataStores and free IPv4 addresses of the
+// Networks joined to the HypervisorGroup or its Hypervisors are gathered from
+// the OnApp, then placement is simulated with SimulateCapacity. DataStores
+// joined to a single Hypervisor are counted as available to all of them.
+func (s *HypervisorGroupsServiceOp) PlanCapacity(ctx context.Context, id int, instancePackageID int, count int, opts *CapacityPlanOptions) (*CapacityPlan, *Response, error) {
+	if id < 1 || instancePackageID < 1 {
+		return nil, nil, godo.NewArgError("id || instancePackageID", "cannot be less than 1")
+	}
+
+	pkg, resp, err := s.client.InstancePackages.Get(ctx, instancePackageID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res, resp, err := s.capacityResources(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	plan, err := SimulateCapacity(res, pkg, count, opts)
+
+	return plan, resp, err
+}
+
+func (s *HypervisorGroupsServiceOp) capacityResources(ctx context.Context, id int) (*CapacityResources, *Response, error) {
+	res := &CapacityResources{
+		UsedCpus: make(map[int]int),
+	}
+
+	hvs, resp, err := s.ListOfAttachedComputeResources(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+	res.Hypervisors = hvs
+
+	for _, hv := range hvs {
+		vms, resp, err := s.client.VirtualMachines.Select(ctx, &VirtualMachineSelector{HypervisorID: hv.ID})
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, vm := range vms {
+			res.UsedCpus[hv.ID] += vm.Cpus
+		}
+	}
+
+	targets := []JoinTarget{HypervisorGroupJoinTarget(id)}
+	for _, hv := range hvs {
+		targets = append(targets, HypervisorJoinTarget(hv.ID))
+	}
+
+	dataStores := make(map[int]bool)
+	networks := make(map[int]bool)
+	for _, target := range targets {
+		target := target
+
+		var dsJoins []DataStoreJoin
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.client.DataStoreJoins.ListByTarget(ctx, target, opt)
+			dsJoins = append(dsJoins, lst...)
+			return len(lst), resp, err
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, join := range dsJoins {
+			if dataStores[join.DataStoreID] {
+				continue
+			}
+			dataStores[join.DataStoreID] = true
+
+			ds, resp, err := s.client.DataStores.Get(ctx, join.DataStoreID)
+			if err != nil {
+				return nil, resp, err
+			}
+			res.DataStores = append(res.DataStores, *ds)
+		}
+
+		var networkJoins []NetworkJoin
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.client.NetworkJoins.ListByTarget(ctx, target, opt)
+			networkJoins = append(networkJoins, lst...)
+			return len(lst), resp, err
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, join := range networkJoins {
+			if networks[join.NetworkID] {
+				continue
+			}
+			networks[join.NetworkID] = true
+
+			free, resp, err := s.freeIPv4Addresses(ctx, join.NetworkID)
+			if err != nil {
+				return nil, resp, err
+			}
+			res.FreeIPAddresses += free
+		}
+	}
+
+	return res, resp, nil
+}
+
+func (s *HypervisorGroupsServiceOp) freeIPv4Addresses(ctx context.Context, networkID int) (int, *Response, error) {
+	var nets []IPNet
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.IPNets.List(ctx, networkID, opt)
+		nets = append(nets, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return 0, resp, err
+	}
+
+	count := new(big.Int)
+	for _, ipNet := range nets {
+		if !ipNet.Ipv4 {
+			continue
+		}
+
+		free, r, err := s.client.IPAM.CountFreeIPNetAddresses(ctx, networkID, ipNet.ID)
+		if err != nil {
+			return 0, r, err
+		}
+		resp = r
+		count.Add(count, free)
+	}
+
+	// clamp to int of the platform
+	maxInt := int(^uint(0) >> 1)
+	if count.Cmp(big.NewInt(int64(maxInt))) > 0 {
+		return maxInt, resp, nil
+	}
+
+	return int(count.Int64()), resp, nil
+}
@@ -0,0 +1,119 @@
+package onappgo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateCapacity(t *testing.T) {
+	res := &CapacityResources{
+		Hypervisors: []Hypervisor{
+			{ID: 1, Online: true, Enabled: true, FreeMemory: 4096, TotalCpus: 4},
+			{ID: 2, Online: true, Enabled: true, FreeMemory: 8192, TotalCpus: 8},
+			{ID: 3, Online: true, Enabled: true, FreeMemory: 65536, TotalCpus: 64, Spare: true},
+			{ID: 4, Online: false, Enabled: true, FreeMemory: 65536, TotalCpus: 64},
+		},
+		UsedCpus: map[int]int{2: 2},
+		DataStores: []DataStore{
+			{ID: 1, Enabled: true, DataStoreSize: 100},
+			{ID: 2, Enabled: false, DataStoreSize: 1000},
+		},
+		FreeIPAddresses: 10,
+	}
+	pkg := &InstancePackage{Memory: 1024, Cpus: 2, DiskSize: 10}
+
+	plan, err := SimulateCapacity(res, pkg, 3, nil)
+	require.NoError(t, err)
+	require.True(t, plan.Fits())
+	require.Equal(t, 5, plan.MaxVirtualMachines)
+	require.Equal(t, CapacityCPU, plan.Bottleneck)
+	require.Equal(t, []CapacityPlacement{{1, 1}, {1, 1}, {2, 1}}, plan.Placements)
+	require.Equal(t, CapacityHeadroom{Resource: CapacityMemory, Available: 12288, Required: 3072, Left: 9216}, plan.Headroom[0])
+	require.Equal(t, CapacityHeadroom{Resource: CapacityCPU, Available: 10, Required: 6, Left: 4}, plan.Headroom[1])
+
+	plan, err = SimulateCapacity(res, pkg, 3, &CapacityPlanOptions{Strategy: CapacitySpread})
+	require.NoError(t, err)
+	require.Equal(t, []CapacityPlacement{{2, 1}, {2, 1}, {2, 1}}, plan.Placements)
+
+	plan, err = SimulateCapacity(res, pkg, 12, &CapacityPlanOptions{CPUOvercommit: 2})
+	require.NoError(t, err)
+	require.False(t, plan.Fits())
+	require.Equal(t, 10, plan.MaxVirtualMachines)
+	require.Equal(t, CapacityIPAddress, plan.Bottleneck)
+	require.Len(t, plan.Placements, 10)
+
+	_, err = SimulateCapacity(res, &InstancePackage{Memory: 1024}, 1, nil)
+	require.Error(t, err)
+}
+
+func TestHypervisorGroups_PlanCapacity(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// every list must be read page by page
+	paged := func(root string, items ...interface{}) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			require.NotEmpty(t, r.URL.Query().Get("per_page"), r.URL.Path)
+			writeTestPage(t, w, r, root, items)
+		}
+	}
+
+	mux.HandleFunc("/instance_packages/5.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"instance_package":{"id":5,"memory":1024,"cpus":2,"disk_size":10}}`)
+	})
+
+	mux.HandleFunc("/settings/hypervisor_zones/1/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"hypervisor":{"id":2,"online":true,"enabled":true,"free_memory":8192,"total_cpus":8}},
+			{"hypervisor":{"id":3,"online":true,"enabled":true,"free_memory":8192,"total_cpus":8}}]`)
+	})
+
+	mux.HandleFunc("/settings/hypervisors/2/virtual_machines.json", paged("virtual_machine", VirtualMachine{ID: 7, HypervisorID: 2, Cpus: 2}))
+	mux.HandleFunc("/settings/hypervisors/3/virtual_machines.json", paged("virtual_machine"))
+
+	// DataStore 10 is joined to the HypervisorGroup and the Hypervisor 2,
+	// DataStore 11 only to the Hypervisor 2
+	mux.HandleFunc("/settings/hypervisor_zones/1/data_store_joins.json", paged("data_store_join", DataStoreJoin{ID: 1, DataStoreID: 10}))
+	mux.HandleFunc("/settings/hypervisors/2/data_store_joins.json", paged("data_store_join", DataStoreJoin{ID: 2, DataStoreID: 11}, DataStoreJoin{ID: 3, DataStoreID: 10}))
+	mux.HandleFunc("/settings/hypervisors/3/data_store_joins.json", paged("data_store_join"))
+
+	dataStores := 0
+	for id, size := range map[int]int{10: 100, 11: 50} {
+		id, size := id, size
+		mux.HandleFunc(fmt.Sprintf("/settings/data_stores/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			dataStores++
+			fmt.Fprintf(w, `{"data_store":{"id":%d,"enabled":true,"data_store_size":%d}}`, id, size)
+		})
+	}
+
+	// Network 20 is joined to the HypervisorGroup and the Hypervisor 3
+	mux.HandleFunc("/settings/hypervisor_zones/1/network_joins.json", paged("networking_network_join", NetworkJoin{ID: 1, NetworkID: 20}))
+	mux.HandleFunc("/settings/hypervisors/2/network_joins.json", paged("networking_network_join"))
+	mux.HandleFunc("/settings/hypervisors/3/network_joins.json", paged("networking_network_join", NetworkJoin{ID: 2, NetworkID: 20}, NetworkJoin{ID: 3, NetworkID: 21}))
+
+	mux.HandleFunc("/settings/networks/20/ip_nets.json", paged("ip_net",
+		IPNet{ID: 30, NetworkAddress: "10.0.0.0", NetworkMask: 24, Ipv4: true},
+		IPNet{ID: 31, NetworkAddress: "fd00::", NetworkMask: 64}))
+	mux.HandleFunc("/settings/networks/21/ip_nets.json", paged("ip_net"))
+	mux.HandleFunc("/settings/networks/20/ip_nets/30/ip_ranges.json", paged("ip_range",
+		IPRange{ID: 40, StartAddress: "10.0.0.1", EndAddress: "10.0.0.10", Ipv4: true}))
+	mux.HandleFunc("/settings/networks/20/ip_nets/30/ip_ranges/40/ip_addresses.json", paged("ip_address", IPAddress{ID: 50, Address: "10.0.0.1"}))
+
+	plan, _, err := client.HypervisorGroups.PlanCapacity(ctx, 1, 5, 3, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, dataStores)
+	require.True(t, plan.Fits())
+	require.Equal(t, 7, plan.MaxVirtualMachines)
+	require.Equal(t, CapacityCPU, plan.Bottleneck)
+	require.Equal(t, []CapacityHeadroom{
+		{Resource: CapacityMemory, Available: 16384, Required: 3072, Left: 13312},
+		{Resource: CapacityCPU, Available: 14, Required: 6, Left: 8},
+		{Resource: CapacityDisk, Available: 150, Required: 30, Left: 120},
+		{Resource: CapacityIPAddress, Available: 9, Required: 3, Left: 6},
+	}, plan.Headroom)
+
+	_, _, err = client.HypervisorGroups.PlanCapacity(ctx, 0, 5, 3, nil)
+	require.Error(t, err)
+}
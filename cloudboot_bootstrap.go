@@ -0,0 +1,276 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const defaultCloudbootBootstrapInterval = 30 * time.Second
+
+// CloudbootStorageRules - rules to select hardware of the Asset for the
+// integrated storage. Patterns are shell patterns, see path.Match.
+type CloudbootStorageRules struct {
+	// SCSI IDs of disks to select, all disks if empty
+	Disks []string
+
+	// SCSI IDs of disks which are never selected, e.g. the boot disk
+	ExcludeDisks []string
+
+	// Type of NICs by MAC pattern, the first matched rule wins, NICs not
+	// matched are not used
+	Nics []CloudbootNicRule
+
+	// PCI addresses of custom devices to select, none if empty
+	CustomPcis []string
+}
+
+// CloudbootNicRule - type of NICs with MAC matched by the pattern
+type CloudbootNicRule struct {
+	Mac  string
+	Type int
+}
+
+// CloudbootBootstrapRequest -
+type CloudbootBootstrapRequest struct {
+	// Options of the CloudbootComputeResource, Mac, PxeIPAddressID and
+	// Storage are filled by the Bootstrap if not set
+	CreateRequest CloudbootComputeResourceCreateRequest
+
+	// PXE IP address created if there is no free CloudbootIPAddress
+	IPAddress string
+
+	// Rules to select Storage, Storage is not set if nil
+	StorageRules *CloudbootStorageRules
+
+	// Polling interval of the Hypervisor state, 30 seconds if zero
+	Interval time.Duration
+}
+
+func (d CloudbootBootstrapRequest) String() string {
+	return godo.Stringify(d)
+}
+
+// Storage select disks, NICs and custom PCI devices of the Asset by rules
+func (r *CloudbootStorageRules) Storage(asset *Asset) (*Storage, error) {
+	res := &Storage{}
+
+	for _, disk := range asset.Disks {
+		selected := len(r.Disks) == 0
+		if !selected {
+			ok, err := matchAny(r.Disks, disk.Scsi)
+			if err != nil {
+				return nil, err
+			}
+			selected = ok
+		}
+
+		excluded, err := matchAny(r.ExcludeDisks, disk.Scsi)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Disks = append(res.Disks, StorageDisk{Scsi: disk.Scsi, Selected: selected && !excluded})
+	}
+
+	for _, nic := range asset.Nics {
+		for _, rule := range r.Nics {
+			ok, err := path.Match(strings.ToLower(rule.Mac), strings.ToLower(nic.Mac))
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				res.Nics = append(res.Nics, StorageNic{Mac: nic.Mac, Type: rule.Type})
+				break
+			}
+		}
+	}
+
+	for _, pci := range asset.CustomPcis {
+		ok, err := matchAny(r.CustomPcis, pci.Pci)
+		if err != nil {
+			return nil, err
+		}
+
+		res.CustomPcis = append(res.CustomPcis, StorageCustomPci{Pci: pci.Pci, Selected: ok})
+	}
+
+	return res, nil
+}
+
+func matchAny(patterns []string, value string) (bool, error) {
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, value)
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Bootstrap add CloudBoot compute resource in one go: pick Asset not used by
+// any Hypervisor (or the one with CreateRequest.Mac), reuse free PXE
+// CloudbootIPAddress or create one, select Storage by rules, create the
+// compute resource and wait until it is Online. Created IP address is deleted
+// if the compute resource is not created. If the compute resource does not
+// get Online, it's returned with the error and left with its IP address.
+func (s *CloudbootComputeResourcesServiceOp) Bootstrap(ctx context.Context, bootstrapRequest *CloudbootBootstrapRequest) (*CloudbootComputeResource, *Response, error) {
+	if bootstrapRequest == nil {
+		return nil, nil, godo.NewArgError("CloudbootComputeResource bootstrapRequest", "cannot be nil")
+	}
+
+	createRequest := bootstrapRequest.CreateRequest
+
+	asset, resp, err := s.unusedAsset(ctx, createRequest.Mac)
+	if err != nil {
+		return nil, resp, err
+	}
+	createRequest.Mac = asset.Mac
+
+	if createRequest.Storage == nil && bootstrapRequest.StorageRules != nil {
+		createRequest.Storage, err = bootstrapRequest.StorageRules.Storage(asset)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	var created *CloudbootIPAddress
+	if createRequest.PxeIPAddressID == 0 {
+		var ip *CloudbootIPAddress
+		ip, created, resp, err = s.pxeIPAddress(ctx, bootstrapRequest.IPAddress)
+		if err != nil {
+			return nil, resp, err
+		}
+		createRequest.PxeIPAddressID = ip.ID
+	}
+
+	res, resp, err := s.Create(ctx, &createRequest)
+	if err != nil {
+		return nil, resp, s.deleteCreatedIPAddress(ctx, created, err)
+	}
+
+	interval := bootstrapRequest.Interval
+	if interval <= 0 {
+		interval = defaultCloudbootBootstrapInterval
+	}
+
+	for !res.Online {
+		select {
+		case <-ctx.Done():
+			return res, resp, fmt.Errorf("CloudbootComputeResource [%d] is not online: %s", res.ID, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		cur, r, err := s.Get(ctx, res.ID)
+		if err != nil {
+			return res, r, fmt.Errorf("CloudbootComputeResource [%d] is not online: %s", res.ID, err)
+		}
+		res, resp = cur, r
+	}
+
+	return res, resp, nil
+}
+
+// deleteCreatedIPAddress delete CloudbootIPAddress created by the Bootstrap
+// and return the error of the Bootstrap extended by the error of deletion
+func (s *CloudbootComputeResourcesServiceOp) deleteCreatedIPAddress(ctx context.Context, created *CloudbootIPAddress, err error) error {
+	if created == nil {
+		return err
+	}
+
+	// clean up also when the creation is canceled
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+
+	if _, derr := s.client.CloudbootIPAddresses.Delete(ctx, created.ID, nil); derr != nil {
+		return fmt.Errorf("%s, CloudbootIPAddress [%d] is not deleted: %s", err, created.ID, derr)
+	}
+
+	return err
+}
+
+func (s *CloudbootComputeResourcesServiceOp) unusedAsset(ctx context.Context, mac string) (*Asset, *Response, error) {
+	var assets []Asset
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.availableResources(ctx, opt)
+		assets = append(assets, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var hvs []CloudbootComputeResource
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, opt)
+		hvs = append(hvs, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	used := make(map[string]bool, len(hvs))
+	for _, hv := range hvs {
+		used[strings.ToLower(hv.Mac)] = true
+	}
+
+	for i := range assets {
+		asset := &assets[i]
+		if used[strings.ToLower(asset.Mac)] {
+			continue
+		}
+
+		if mac == "" || strings.EqualFold(mac, asset.Mac) {
+			return asset, resp, nil
+		}
+	}
+
+	if mac != "" {
+		return nil, resp, fmt.Errorf("Asset [%s] is not available or already used", mac)
+	}
+
+	return nil, resp, fmt.Errorf("no unused Asset available")
+}
+
+// pxeIPAddress return free CloudbootIPAddress or create a new one with the
+// address, created one is also returned as second value
+func (s *CloudbootComputeResourcesServiceOp) pxeIPAddress(ctx context.Context, address string) (*CloudbootIPAddress, *CloudbootIPAddress, *Response, error) {
+	var lst []CloudbootIPAddress
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		page, resp, err := s.client.CloudbootIPAddresses.List(ctx, opt)
+		lst = append(lst, page...)
+		return len(page), resp, err
+	})
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	for i := range lst {
+		if lst[i].Free && lst[i].HypervisorID == 0 && (address == "" || lst[i].Address == address) {
+			return &lst[i], nil, resp, nil
+		}
+	}
+
+	if address == "" {
+		return nil, nil, resp, fmt.Errorf("no free CloudbootIPAddress, set IPAddress to create one")
+	}
+
+	ip, resp, err := s.client.CloudbootIPAddresses.Create(ctx, &CloudbootIPAddressCreateRequest{Address: address})
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	return ip, ip, resp, nil
+}
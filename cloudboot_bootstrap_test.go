@@ -0,0 +1,146 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudbootStorageRules_Storage(t *testing.T) {
+	asset := &Asset{
+		Mac:        "00:11:22:33:44:55",
+		Disks:      []StorageDisk{{Scsi: "0:0:0:0"}, {Scsi: "1:0:0:0"}, {Scsi: "1:0:1:0"}},
+		Nics:       []StorageNic{{Mac: "AA:BB:CC:00:00:01"}, {Mac: "AA:BB:CC:DD:00:02"}, {Mac: "00:11:22:33:44:55"}},
+		CustomPcis: []StorageCustomPci{{Pci: "0000:03:00.0"}, {Pci: "0000:04:00.0"}},
+	}
+	rules := &CloudbootStorageRules{
+		ExcludeDisks: []string{"0:*"},
+		Nics:         []CloudbootNicRule{{Mac: "aa:bb:cc:dd:*", Type: 2}, {Mac: "aa:bb:cc:*", Type: 1}},
+		CustomPcis:   []string{"0000:03:*"},
+	}
+
+	storage, err := rules.Storage(asset)
+	require.NoError(t, err)
+	require.Equal(t, &Storage{
+		Disks:      []StorageDisk{{Scsi: "0:0:0:0"}, {Scsi: "1:0:0:0", Selected: true}, {Scsi: "1:0:1:0", Selected: true}},
+		Nics:       []StorageNic{{Mac: "AA:BB:CC:00:00:01", Type: 1}, {Mac: "AA:BB:CC:DD:00:02", Type: 2}},
+		CustomPcis: []StorageCustomPci{{Pci: "0000:03:00.0", Selected: true}, {Pci: "0000:04:00.0"}},
+	}, storage)
+
+	_, err = (&CloudbootStorageRules{Disks: []string{"["}}).Storage(asset)
+	require.Error(t, err)
+}
+
+func TestCloudbootComputeResources_Bootstrap(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// every list must be read page by page
+	paged := func(root string, items ...interface{}) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			require.NotEmpty(t, r.URL.Query().Get("per_page"), r.URL.Path)
+			writeTestPage(t, w, r, root, items)
+		}
+	}
+
+	mux.HandleFunc("/settings/assets.json", paged("asset",
+		Asset{Mac: "00:00:00:00:00:01"},
+		Asset{Mac: "00:00:00:00:00:02", Disks: []StorageDisk{{Scsi: "0:0:0:0"}}}))
+
+	// the Hypervisor which uses the first Asset is on the second page
+	var hvs []interface{}
+	for i := 100; i < 200; i++ {
+		hvs = append(hvs, CloudbootComputeResource{ID: i, Mac: fmt.Sprintf("00:00:00:00:01:%02x", i)})
+	}
+	hvs = append(hvs, CloudbootComputeResource{ID: 1, Mac: "00:00:00:00:00:01"})
+	mux.HandleFunc("/settings/hypervisors.json", paged("hypervisor", hvs...))
+
+	mux.HandleFunc("/cloud_boot_ip_addresses.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			paged("ip_address", CloudbootIPAddress{ID: 3, Address: "10.0.0.3", HypervisorID: 1})(w, r)
+		case http.MethodPost:
+			fmt.Fprint(w, `{"ip_address":{"id":4,"address":"10.0.0.4","free":true}}`)
+		}
+	})
+
+	deleted := false
+	mux.HandleFunc("/cloud_boot_ip_addresses/4.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = true
+	})
+
+	fail := true
+	mux.HandleFunc("/settings/assets/00:00:00:00:00:02/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if fail {
+			http.Error(w, `{"errors":["failed"]}`, http.StatusUnprocessableEntity)
+			return
+		}
+
+		var got map[string]CloudbootComputeResourceCreateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, 4, got["hypervisor"].PxeIPAddressID)
+		require.Equal(t, []StorageDisk{{Scsi: "0:0:0:0", Selected: true}}, got["hypervisor"].Storage.Disks)
+
+		fmt.Fprint(w, `{"hypervisor":{"id":2,"online":false}}`)
+	})
+
+	polls := 0
+	broken := false
+	offline := false
+	mux.HandleFunc("/settings/hypervisors/2.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if broken {
+			http.Error(w, `{"errors":["failed"]}`, http.StatusInternalServerError)
+			return
+		}
+		polls++
+		fmt.Fprintf(w, `{"hypervisor":{"id":2,"online":%t}}`, polls > 1 && !offline)
+	})
+
+	bootstrapRequest := &CloudbootBootstrapRequest{
+		CreateRequest: CloudbootComputeResourceCreateRequest{Label: "cb", HypervisorType: "kvm"},
+		IPAddress:     "10.0.0.4",
+		StorageRules:  &CloudbootStorageRules{},
+		Interval:      time.Millisecond,
+	}
+
+	// created IP address is deleted if the compute resource is not created
+	_, _, err := client.CloudbootComputeResources.Bootstrap(ctx, bootstrapRequest)
+	require.Error(t, err)
+	require.True(t, deleted)
+
+	deleted = false
+	fail = false
+	hv, _, err := client.CloudbootComputeResources.Bootstrap(ctx, bootstrapRequest)
+	require.NoError(t, err)
+	require.True(t, hv.Online)
+	require.Equal(t, 2, polls)
+
+	// the compute resource which is not online is returned and left with
+	// its IP address, the handler of the compute resource accepts only GET
+	broken = true
+	hv, _, err = client.CloudbootComputeResources.Bootstrap(ctx, bootstrapRequest)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not online")
+	require.Equal(t, 2, hv.ID)
+	require.False(t, deleted)
+
+	broken = false
+	offline = true
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	hv, _, err = client.CloudbootComputeResources.Bootstrap(timeout, bootstrapRequest)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not online")
+	require.Equal(t, 2, hv.ID)
+	require.False(t, hv.Online)
+	require.False(t, deleted)
+}
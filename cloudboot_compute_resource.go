@@ -24,6 +24,8 @@ type CloudbootComputeResourcesService interface {
 	Edit(context.Context, int, *CloudbootComputeResourceEditRequest) (*Response, error)
 
 	CloudbootAvailableResources(context.Context) ([]Asset, *Response, error)
+
+	Bootstrap(context.Context, *CloudbootBootstrapRequest) (*CloudbootComputeResource, *Response, error)
 }
 
 // CloudbootComputeResourcesServiceOp handles communication with the CloudbootComputeResource related methods of the
@@ -39,6 +41,11 @@ type CloudbootComputeResource Hypervisor
 type Asset struct {
 	Mac string `json:"mac,omitempty"`
 	IP  string `json:"ip,omitempty"`
+
+	// Hardware reported by the asset, used to select Storage
+	Disks      []StorageDisk      `json:"disks,omitempty"`
+	Nics       []StorageNic       `json:"nics,omitempty"`
+	CustomPcis []StorageCustomPci `json:"custom_pcis,omitempty"`
 }
 
 type StorageDisk struct {
@@ -225,8 +232,13 @@ func (s *CloudbootComputeResourcesServiceOp) Edit(ctx context.Context, id int, e
 
 // CloudbootAvailableResources - List all Cloudboot available resources
 func (s *CloudbootComputeResourcesServiceOp) CloudbootAvailableResources(ctx context.Context) ([]Asset, *Response, error) {
+	return s.availableResources(ctx, nil)
+}
+
+// availableResources list one page of Cloudboot available resources
+func (s *CloudbootComputeResourcesServiceOp) availableResources(ctx context.Context, opt *ListOptions) ([]Asset, *Response, error) {
 	path := cloudBootAvailableResourcesBasePath + apiFormat
-	path, err := addOptions(path, nil)
+	path, err := addOptions(path, opt)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,103 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+)
+
+const consoleBasePath string = "virtual_machines/%d/console"
+const consoleRemoteBasePath string = "console_remote/%s"
+
+// RemoteAccessSession represent console session of the VirtualMachine
+type RemoteAccessSession struct {
+	CreatedAt        string `json:"created_at,omitempty"`
+	ID               int    `json:"id,omitempty"`
+	Port             int    `json:"port,omitempty"`
+	RemoteKey        string `json:"remote_key,omitempty"`
+	UpdatedAt        string `json:"updated_at,omitempty"`
+	VirtualMachineID int    `json:"virtual_machine_id,omitempty"`
+}
+
+type remoteAccessSessionRoot struct {
+	RemoteAccessSession *RemoteAccessSession `json:"remote_access_session"`
+}
+
+// Console represent connection details of the VirtualMachine console
+type Console struct {
+	Session *RemoteAccessSession
+
+	// VNC endpoint on the hypervisor
+	Host     string
+	Port     int
+	Password string
+
+	// URL of the console page at the Control Panel
+	URL string
+}
+
+// Address return VNC endpoint as host:port
+func (c *Console) Address() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+func (c Console) String() string {
+	return godo.Stringify(c)
+}
+
+// Console request console access to the VirtualMachine and return connection details
+func (s *VirtualMachinesServiceOp) Console(ctx context.Context, id int) (*Console, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(consoleBasePath, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("VirtualMachine [Console]  req: ", req)
+
+	root := new(remoteAccessSessionRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if root.RemoteAccessSession == nil {
+		return nil, resp, fmt.Errorf("Console: remote access session for VirtualMachine [%d] not returned", id)
+	}
+
+	// remote access address and password become known only after session is requested
+	vm, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res := &Console{
+		Session:  root.RemoteAccessSession,
+		Host:     vm.LocalRemoteAccessIPAddress,
+		Port:     vm.LocalRemoteAccessPort,
+		Password: vm.RemoteAccessPassword,
+	}
+
+	if res.Port == 0 {
+		res.Port = root.RemoteAccessSession.Port
+	}
+
+	if root.RemoteAccessSession.RemoteKey != "" {
+		rel, err := url.Parse(fmt.Sprintf(consoleRemoteBasePath, root.RemoteAccessSession.RemoteKey))
+		if err != nil {
+			return nil, resp, err
+		}
+		res.URL = s.client.BaseURL.ResolveReference(rel).String()
+	}
+
+	return res, resp, err
+}
@@ -0,0 +1,227 @@
+package onappgo
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/digitalocean/godo"
+	"golang.org/x/net/websocket"
+)
+
+const defaultConsoleProxyAddress string = "127.0.0.1:0"
+
+// ConsoleProxyOptions -
+type ConsoleProxyOptions struct {
+	// Local address to listen on, "127.0.0.1:0" if empty
+	ListenAddress string
+
+	// Accept WebSocket connections (e.g. from noVNC) instead of raw TCP ones
+	WebSocket bool
+
+	// Connect to the console over WebSocket (ws:// or wss://) instead of
+	// raw TCP connection to the Console.Address
+	UpstreamURL string
+
+	// Used for wss:// upstream
+	TLSConfig *tls.Config
+}
+
+// ConsoleProxy forwards local connections to the VirtualMachine console,
+// so any VNC client can be attached to the listener address.
+type ConsoleProxy struct {
+	console  *Console
+	opts     ConsoleProxyOptions
+	listener net.Listener
+
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	conns  map[io.Closer]struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewConsoleProxy start listening for the local connections to the console.
+// Connections are accepted after Serve is called.
+func NewConsoleProxy(console *Console, opts *ConsoleProxyOptions) (*ConsoleProxy, error) {
+	if console == nil {
+		return nil, godo.NewArgError("console", "cannot be nil")
+	}
+
+	p := &ConsoleProxy{
+		console: console,
+		conns:   make(map[io.Closer]struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	if opts != nil {
+		p.opts = *opts
+	}
+
+	if p.opts.UpstreamURL == "" && (console.Host == "" || console.Port < 1) {
+		return nil, godo.NewArgError("console", "remote access address is not set")
+	}
+
+	if p.opts.ListenAddress == "" {
+		p.opts.ListenAddress = defaultConsoleProxyAddress
+	}
+
+	l, err := net.Listen("tcp", p.opts.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+	p.listener = l
+
+	return p, nil
+}
+
+// Addr return local address of the proxy
+func (p *ConsoleProxy) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Serve accept local connections until ctx is done or proxy is closed
+func (p *ConsoleProxy) Serve(ctx context.Context) error {
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Close()
+		case <-p.closed:
+		}
+	}()
+
+	var err error
+	if p.opts.WebSocket {
+		srv := &http.Server{
+			Handler: websocket.Server{
+				// local listener, any origin is allowed
+				Handshake: func(*websocket.Config, *http.Request) error { return nil },
+				Handler: func(ws *websocket.Conn) {
+					ws.PayloadType = websocket.BinaryFrame
+					p.forward(ctx, ws)
+				},
+			},
+		}
+		err = srv.Serve(p.listener)
+	} else {
+		for {
+			conn, aerr := p.listener.Accept()
+			if aerr != nil {
+				err = aerr
+				break
+			}
+
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				p.forward(ctx, conn)
+			}()
+		}
+	}
+
+	p.wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// Close stop listening and close all proxied connections
+func (p *ConsoleProxy) Close() error {
+	var err error
+	p.once.Do(func() {
+		close(p.closed)
+		err = p.listener.Close()
+	})
+
+	p.mu.Lock()
+	for c := range p.conns {
+		c.Close()
+	}
+	p.mu.Unlock()
+
+	return err
+}
+
+func (p *ConsoleProxy) dial(ctx context.Context) (net.Conn, error) {
+	if p.opts.UpstreamURL == "" {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", p.console.Address())
+	}
+
+	cfg, err := websocket.NewConfig(p.opts.UpstreamURL, p.opts.UpstreamURL)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TlsConfig = p.opts.TLSConfig
+
+	ws, err := websocket.DialConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ws.PayloadType = websocket.BinaryFrame
+
+	return ws, nil
+}
+
+func (p *ConsoleProxy) track(c io.Closer, add bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if add {
+		p.conns[c] = struct{}{}
+	} else {
+		delete(p.conns, c)
+	}
+}
+
+func (p *ConsoleProxy) forward(ctx context.Context, local net.Conn) {
+	p.track(local, true)
+	defer func() {
+		local.Close()
+		p.track(local, false)
+	}()
+
+	upstream, err := p.dial(ctx)
+	if err != nil {
+		log.Printf("ConsoleProxy [forward] connect to console of VirtualMachine failed: %s\n", err)
+		return
+	}
+
+	p.track(upstream, true)
+	defer func() {
+		upstream.Close()
+		p.track(upstream, false)
+	}()
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst io.Writer, src io.Reader) {
+		io.Copy(dst, src)
+		done <- struct{}{}
+	}
+
+	go pipe(upstream, local)
+	go pipe(local, upstream)
+
+	// close both sides as soon as any of them is finished
+	<-done
+}
+
+func (p *ConsoleProxy) String() string {
+	return fmt.Sprintf("ConsoleProxy[%s -> %s]", p.Addr(), p.upstream())
+}
+
+func (p *ConsoleProxy) upstream() string {
+	if p.opts.UpstreamURL != "" {
+		return p.opts.UpstreamURL
+	}
+
+	return p.console.Address()
+}
@@ -0,0 +1,134 @@
+package onappgo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+const testRFBVersion = "RFB 003.008\n"
+
+// stubVNC accept connections, send RFB version and echo all received data back
+func stubVNC(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(c net.Conn) {
+				defer c.Close()
+				fmt.Fprint(c, testRFBVersion)
+
+				r := bufio.NewReader(c)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fmt.Fprint(c, line)
+				}
+			}(conn)
+		}
+	}()
+
+	return l
+}
+
+func testConsole(t *testing.T, vnc net.Listener) *Console {
+	host, port, _ := net.SplitHostPort(vnc.Addr().String())
+
+	mux.HandleFunc("/virtual_machines/1/console.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{"remote_access_session":{"id":5,"port":5901,"remote_key":"abc","virtual_machine_id":1}}`)
+	})
+
+	mux.HandleFunc("/virtual_machines/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"virtual_machine":{"id":1,"local_remote_access_ip_address":"%s","local_remote_access_port":%s,"remote_access_password":"secret"}}`, host, port)
+	})
+
+	console, _, err := client.VirtualMachines.Console(ctx, 1)
+	require.NoError(t, err)
+
+	expectedPort, _ := strconv.Atoi(port)
+	require.Equal(t, host, console.Host)
+	require.Equal(t, expectedPort, console.Port)
+	require.Equal(t, "secret", console.Password)
+	require.Equal(t, 5, console.Session.ID)
+	require.Equal(t, server.URL+"/console_remote/abc", console.URL)
+
+	return console
+}
+
+func TestConsoleProxy_TCP(t *testing.T) {
+	setup()
+	defer teardown()
+
+	vnc := stubVNC(t)
+	defer vnc.Close()
+
+	proxy, err := NewConsoleProxy(testConsole(t, vnc), nil)
+	require.NoError(t, err)
+
+	c, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- proxy.Serve(c) }()
+
+	conn, err := net.Dial("tcp", proxy.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, testRFBVersion, line)
+
+	fmt.Fprint(conn, "ping\n")
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "ping\n", line)
+
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+}
+
+func TestConsoleProxy_WebSocket(t *testing.T) {
+	setup()
+	defer teardown()
+
+	vnc := stubVNC(t)
+	defer vnc.Close()
+
+	proxy, err := NewConsoleProxy(testConsole(t, vnc), &ConsoleProxyOptions{WebSocket: true})
+	require.NoError(t, err)
+	defer proxy.Close()
+
+	go proxy.Serve(ctx)
+
+	addr := proxy.Addr().String()
+	ws, err := websocket.Dial("ws://"+addr+"/", "", "http://"+addr+"/")
+	require.NoError(t, err)
+	defer ws.Close()
+
+	r := bufio.NewReader(ws)
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, testRFBVersion, line)
+
+	fmt.Fprint(ws, "ping\n")
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "ping\n", line)
+}
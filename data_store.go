@@ -25,6 +25,8 @@ type DataStoresService interface {
 	// TODO !!!
 	// Move next functions to the DataStoreActionsService
 	IoLimits(context.Context, int, *IoLimits) (*Response, error)
+
+	Report(context.Context, *DataStoreReportThresholds) (*DataStoreReport, *Response, error)
 }
 
 // DataStoresServiceOp handles communication with the Data Store related methods of the
@@ -10,8 +10,8 @@ import (
 )
 
 var dataStoreJoinPaths = map[string]string{
-	"Hypervisor":      "settings/hypervisors/%d/data_store_joins",
-	"HypervisorGroup": "settings/hypervisor_zones/%d/data_store_joins",
+	JoinTargetHypervisor:      "settings/hypervisors/%d/data_store_joins",
+	JoinTargetHypervisorGroup: "settings/hypervisor_zones/%d/data_store_joins",
 }
 
 // DataStoreJoinsService is an interface for interfacing with the DataStoreJoin
@@ -20,6 +20,8 @@ type DataStoreJoinsService interface {
 	Get(context.Context, string, int, int) (*DataStoreJoin, *Response, error)
 	Create(context.Context, *DataStoreJoinCreateRequest) (*DataStoreJoin, *Response, error)
 	Delete(context.Context, *DataStoreJoinDeleteRequest, interface{}) (*Response, error)
+	ListByTarget(context.Context, JoinTarget, *ListOptions) ([]DataStoreJoin, *Response, error)
+	Sync(context.Context, JoinTarget, []int, *JoinSyncOptions) (*JoinSyncReport, *Response, error)
 }
 
 // DataStoreJoinsServiceOp -
@@ -189,3 +191,50 @@ func (s *DataStoreJoinsServiceOp) Delete(ctx context.Context, deleteRequest *Dat
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// ListByTarget - List DataStoreJoins of the Hypervisor or HypervisorGroup
+func (s *DataStoreJoinsServiceOp) ListByTarget(ctx context.Context, target JoinTarget, opt *ListOptions) ([]DataStoreJoin, *Response, error) {
+	if err := target.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.List(ctx, &DataStoreJoinCreateRequest{TargetJoinType: target.Type, TargetJoinID: target.ID}, opt)
+}
+
+// Sync DataStoreJoins of the target with desired DataStore IDs
+func (s *DataStoreJoinsServiceOp) Sync(ctx context.Context, target JoinTarget, dataStoreIDs []int, opts *JoinSyncOptions) (*JoinSyncReport, *Response, error) {
+	var joins []DataStoreJoin
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.ListByTarget(ctx, target, opt)
+		joins = append(joins, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	current := make([]joinItem, len(joins))
+	for i, v := range joins {
+		current[i] = joinItem{joinID: v.ID, resourceID: v.DataStoreID}
+	}
+
+	desired := make([]joinItem, len(dataStoreIDs))
+	for i, id := range dataStoreIDs {
+		desired[i] = joinItem{resourceID: id}
+	}
+
+	remove := func(v joinItem) (*Response, error) {
+		return s.Delete(ctx, &DataStoreJoinDeleteRequest{ID: v.joinID, TargetJoinType: target.Type, TargetJoinID: target.ID}, nil)
+	}
+
+	add := func(v joinItem) (*Response, error) {
+		_, resp, err := s.Create(ctx, &DataStoreJoinCreateRequest{
+			DataStoreID:    v.resourceID,
+			TargetJoinType: target.Type,
+			TargetJoinID:   target.ID,
+		})
+		return resp, err
+	}
+
+	return syncJoins(target, current, desired, opts, remove, add)
+}
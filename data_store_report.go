@@ -0,0 +1,293 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report output formats
+const (
+	ReportTable = "table"
+	ReportJSON  = "json"
+	ReportCSV   = "csv"
+)
+
+// DataStoreReportThresholds - alert is raised if value of the DataStore
+// reaches the threshold, zero threshold is not checked
+type DataStoreReportThresholds struct {
+	// Used space in percents of the size
+	UsagePercent float64
+
+	// Zombie disks space in percents of the size
+	ZombiePercent float64
+
+	// Ratio of the size of disks placed on the DataStore to its size
+	Overcommit float64
+}
+
+// DataStoreReportDisk - Disk placed on the DataStore
+type DataStoreReportDisk struct {
+	ID                  int    `json:"id"`
+	Label               string `json:"label"`
+	Size                int    `json:"size"`
+	VirtualMachineID    int    `json:"virtual_machine_id,omitempty"`
+	VirtualMachineLabel string `json:"virtual_machine_label,omitempty"`
+}
+
+// DataStoreReportRow - capacity of the single DataStore, sizes are in GB
+type DataStoreReportRow struct {
+	ID                  int                   `json:"id"`
+	Label               string                `json:"label"`
+	DataStoreGroupID    int                   `json:"data_store_group_id,omitempty"`
+	DataStoreGroupLabel string                `json:"data_store_group_label,omitempty"`
+	Size                int                   `json:"size"`
+	Usage               int                   `json:"usage"`
+	Free                int                   `json:"free"`
+	ZombieSize          int                   `json:"zombie_size"`
+	Allocated           int                   `json:"allocated"`
+	UsagePercent        float64               `json:"usage_percent"`
+	ZombiePercent       float64               `json:"zombie_percent"`
+	Overcommit          float64               `json:"overcommit"`
+	Disks               []DataStoreReportDisk `json:"disks"`
+	VirtualMachineIDs   []int                 `json:"virtual_machine_ids"`
+	Alerts              []string              `json:"alerts,omitempty"`
+}
+
+// DataStoreGroupReport - totals of the DataStores of the DataStoreGroup,
+// DataStores without group are summed with ID 0
+type DataStoreGroupReport struct {
+	ID         int    `json:"id"`
+	Label      string `json:"label"`
+	DataStores int    `json:"data_stores"`
+	Size       int    `json:"size"`
+	Usage      int    `json:"usage"`
+	ZombieSize int    `json:"zombie_size"`
+	Allocated  int    `json:"allocated"`
+}
+
+// DataStoreReport - capacity, zombie space and consumers of DataStores
+type DataStoreReport struct {
+	DataStores []DataStoreReportRow   `json:"data_stores"`
+	Groups     []DataStoreGroupReport `json:"data_store_groups"`
+}
+
+var dataStoreReportColumns = []string{
+	"id", "label", "group", "size", "usage", "usage_percent", "free",
+	"zombie_size", "zombie_percent", "allocated", "overcommit", "disks", "virtual_machines", "alerts",
+}
+
+// BuildDataStoreReport map Disks and VirtualMachines to DataStores, sum them
+// by DataStoreGroups and check thresholds
+func BuildDataStoreReport(dss []DataStore, groups []DataStoreGroup, disks []Disk, vms []VirtualMachine, thresholds *DataStoreReportThresholds) *DataStoreReport {
+	if thresholds == nil {
+		thresholds = &DataStoreReportThresholds{}
+	}
+
+	groupLabels := make(map[int]string, len(groups))
+	for _, v := range groups {
+		groupLabels[v.ID] = v.Label
+	}
+
+	vmLabels := make(map[int]string, len(vms))
+	for _, v := range vms {
+		vmLabels[v.ID] = v.Label
+	}
+
+	byDataStore := make(map[int][]Disk)
+	for _, v := range disks {
+		byDataStore[v.DataStoreID] = append(byDataStore[v.DataStoreID], v)
+	}
+
+	res := &DataStoreReport{}
+	totals := make(map[int]*DataStoreGroupReport)
+
+	for _, ds := range dss {
+		row := DataStoreReportRow{
+			ID:                  ds.ID,
+			Label:               ds.Label,
+			DataStoreGroupID:    ds.DataStoreGroupID,
+			DataStoreGroupLabel: groupLabels[ds.DataStoreGroupID],
+			Size:                ds.DataStoreSize,
+			Usage:               ds.Usage,
+			Free:                ds.DataStoreSize - ds.Usage,
+			ZombieSize:          ds.ZombieDisksSize,
+			Disks:               []DataStoreReportDisk{},
+			VirtualMachineIDs:   []int{},
+		}
+
+		seen := make(map[int]bool)
+		for _, d := range byDataStore[ds.ID] {
+			row.Allocated += d.DiskSize
+			row.Disks = append(row.Disks, DataStoreReportDisk{
+				ID:                  d.ID,
+				Label:               d.Label,
+				Size:                d.DiskSize,
+				VirtualMachineID:    d.VirtualMachineID,
+				VirtualMachineLabel: vmLabels[d.VirtualMachineID],
+			})
+
+			if d.VirtualMachineID > 0 && !seen[d.VirtualMachineID] {
+				seen[d.VirtualMachineID] = true
+				row.VirtualMachineIDs = append(row.VirtualMachineIDs, d.VirtualMachineID)
+			}
+		}
+		sort.Ints(row.VirtualMachineIDs)
+
+		if row.Size > 0 {
+			row.UsagePercent = float64(row.Usage) * 100 / float64(row.Size)
+			row.ZombiePercent = float64(row.ZombieSize) * 100 / float64(row.Size)
+			row.Overcommit = float64(row.Allocated) / float64(row.Size)
+		}
+
+		if thresholds.UsagePercent > 0 && row.UsagePercent >= thresholds.UsagePercent {
+			row.Alerts = append(row.Alerts, fmt.Sprintf("usage %.1f%% reached %.1f%%", row.UsagePercent, thresholds.UsagePercent))
+		}
+		if thresholds.ZombiePercent > 0 && row.ZombiePercent >= thresholds.ZombiePercent {
+			row.Alerts = append(row.Alerts, fmt.Sprintf("zombie disks %.1f%% reached %.1f%%", row.ZombiePercent, thresholds.ZombiePercent))
+		}
+		if thresholds.Overcommit > 0 && row.Overcommit >= thresholds.Overcommit {
+			row.Alerts = append(row.Alerts, fmt.Sprintf("overcommit %.2f reached %.2f", row.Overcommit, thresholds.Overcommit))
+		}
+
+		res.DataStores = append(res.DataStores, row)
+
+		total, ok := totals[ds.DataStoreGroupID]
+		if !ok {
+			total = &DataStoreGroupReport{ID: ds.DataStoreGroupID, Label: groupLabels[ds.DataStoreGroupID]}
+			totals[ds.DataStoreGroupID] = total
+		}
+		total.DataStores++
+		total.Size += row.Size
+		total.Usage += row.Usage
+		total.ZombieSize += row.ZombieSize
+		total.Allocated += row.Allocated
+	}
+
+	for _, v := range totals {
+		res.Groups = append(res.Groups, *v)
+	}
+	sort.Slice(res.Groups, func(i, j int) bool { return res.Groups[i].ID < res.Groups[j].ID })
+
+	return res
+}
+
+// Alerts return rows with raised alerts
+func (r *DataStoreReport) Alerts() []DataStoreReportRow {
+	var res []DataStoreReportRow
+	for _, v := range r.DataStores {
+		if len(v.Alerts) > 0 {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Write report in the format: ReportTable, ReportJSON or ReportCSV. Disks of
+// the DataStores are written only to JSON, table and CSV have their number.
+func (r *DataStoreReport) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportTable:
+		return r.writeTable(w)
+	case ReportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case ReportCSV:
+		return r.writeCSV(w)
+	}
+
+	return fmt.Errorf("report format %q is not supported, use %s, %s or %s", format, ReportTable, ReportJSON, ReportCSV)
+}
+
+func (r *DataStoreReport) records() [][]string {
+	res := make([][]string, len(r.DataStores))
+	for i, v := range r.DataStores {
+		res[i] = []string{
+			strconv.Itoa(v.ID), v.Label, v.DataStoreGroupLabel,
+			strconv.Itoa(v.Size), strconv.Itoa(v.Usage), strconv.FormatFloat(v.UsagePercent, 'f', 1, 64),
+			strconv.Itoa(v.Free), strconv.Itoa(v.ZombieSize), strconv.FormatFloat(v.ZombiePercent, 'f', 1, 64),
+			strconv.Itoa(v.Allocated), strconv.FormatFloat(v.Overcommit, 'f', 2, 64),
+			strconv.Itoa(len(v.Disks)), strconv.Itoa(len(v.VirtualMachineIDs)), strings.Join(v.Alerts, "; "),
+		}
+	}
+
+	return res
+}
+
+func (r *DataStoreReport) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(dataStoreReportColumns))
+	for i, v := range dataStoreReportColumns {
+		header[i] = strings.ToUpper(v)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, record := range r.records() {
+		fmt.Fprintln(tw, strings.Join(record, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func (r *DataStoreReport) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(dataStoreReportColumns); err != nil {
+		return err
+	}
+
+	if err := cw.WriteAll(r.records()); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// Report collect DataStores, DataStoreGroups, Disks and VirtualMachines and
+// build the DataStoreReport
+func (s *DataStoresServiceOp) Report(ctx context.Context, thresholds *DataStoreReportThresholds) (*DataStoreReport, *Response, error) {
+	var dss []DataStore
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, opt)
+		dss = append(dss, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var groups []DataStoreGroup
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.DataStoreGroups.List(ctx, opt)
+		groups = append(groups, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var disks []Disk
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.Disks.List(ctx, opt)
+		disks = append(disks, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	vms, resp, err := s.client.VirtualMachines.Select(ctx, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return BuildDataStoreReport(dss, groups, disks, vms, thresholds), resp, nil
+}
@@ -0,0 +1,98 @@
+package onappgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDataStoreReport(t *testing.T) {
+	dss := []DataStore{
+		{ID: 1, Label: "ds-1", DataStoreGroupID: 10, DataStoreSize: 100, Usage: 90, ZombieDisksSize: 20},
+		{ID: 2, Label: "ds-2", DataStoreGroupID: 10, DataStoreSize: 200, Usage: 50},
+		{ID: 3, Label: "ds-3", DataStoreSize: 50},
+	}
+	groups := []DataStoreGroup{{ID: 10, Label: "zone"}}
+	disks := []Disk{
+		{ID: 1, Label: "root", DataStoreID: 1, DiskSize: 80, VirtualMachineID: 7},
+		{ID: 2, Label: "swap", DataStoreID: 1, DiskSize: 40, VirtualMachineID: 7},
+		{ID: 3, Label: "data", DataStoreID: 2, DiskSize: 50, VirtualMachineID: 8},
+	}
+	vms := []VirtualMachine{{ID: 7, Label: "web"}, {ID: 8, Label: "db"}}
+
+	report := BuildDataStoreReport(dss, groups, disks, vms, &DataStoreReportThresholds{UsagePercent: 85, ZombiePercent: 10, Overcommit: 1})
+
+	ds1 := report.DataStores[0]
+	require.Equal(t, 120, ds1.Allocated)
+	require.Equal(t, 1.2, ds1.Overcommit)
+	require.Equal(t, []int{7}, ds1.VirtualMachineIDs)
+	require.Equal(t, "web", ds1.Disks[0].VirtualMachineLabel)
+	require.Len(t, ds1.Alerts, 3)
+
+	alerts := report.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, 1, alerts[0].ID)
+
+	require.Equal(t, []DataStoreGroupReport{
+		{ID: 0, DataStores: 1, Size: 50},
+		{ID: 10, Label: "zone", DataStores: 2, Size: 300, Usage: 140, ZombieSize: 20, Allocated: 170},
+	}, report.Groups)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, ReportCSV))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "2,ds-2,zone,200,50,25.0,150,0,0.0,50,0.25,1,1,", lines[2])
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, ReportTable))
+	require.True(t, strings.HasPrefix(buf.String(), "ID  LABEL"))
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, ReportJSON))
+	var decoded DataStoreReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, report.DataStores[2].Label, decoded.DataStores[2].Label)
+
+	require.Error(t, report.Write(&buf, "xml"))
+}
+
+func TestDataStores_Report(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// every list must be read page by page
+	paged := func(root string, items ...interface{}) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			require.NotEmpty(t, r.URL.Query().Get("per_page"), r.URL.Path)
+			writeTestPage(t, w, r, root, items)
+		}
+	}
+
+	var dss, groups []interface{}
+	for i := 1; i <= 150; i++ {
+		dss = append(dss, DataStore{ID: i, Label: fmt.Sprintf("ds-%d", i), DataStoreGroupID: 1000 + i, DataStoreSize: 100})
+		groups = append(groups, DataStoreGroup{ID: 1000 + i, Label: fmt.Sprintf("zone-%d", i)})
+	}
+
+	mux.HandleFunc("/settings/data_stores.json", paged("data_store", dss...))
+	mux.HandleFunc("/settings/data_store_zones.json", paged("data_store_group", groups...))
+	mux.HandleFunc("/settings/disks.json", paged("disk", Disk{ID: 1, DataStoreID: 150, DiskSize: 40, VirtualMachineID: 7}))
+	mux.HandleFunc("/virtual_machines.json", paged("virtual_machine", VirtualMachine{ID: 7, Label: "web"}))
+
+	report, _, err := client.DataStores.Report(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, report.DataStores, 150)
+	require.Len(t, report.Groups, 150)
+
+	last := report.DataStores[149]
+	require.Equal(t, 150, last.ID)
+	require.Equal(t, "zone-150", last.DataStoreGroupLabel)
+	require.Equal(t, 40, last.Allocated)
+	require.Equal(t, "web", last.Disks[0].VirtualMachineLabel)
+}
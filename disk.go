@@ -20,6 +20,14 @@ type DisksService interface {
 	Create(context.Context, *DiskCreateRequest) (*Disk, *Response, error)
 	Delete(context.Context, int, interface{}) (*Transaction, *Response, error)
 	Edit(context.Context, int, *DiskEditRequest) (*Response, error)
+
+	Resize(context.Context, int, int, *DiskResizeOptions) (*Transaction, *Response, error)
+	Migrate(context.Context, int, int, *DiskMigrateOptions) (*Transaction, *Response, error)
+
+	EnableAutobackup(context.Context, int) (*Response, error)
+	DisableAutobackup(context.Context, int) (*Response, error)
+	SetIoLimits(context.Context, int, *DiskIoLimits) (*Response, error)
+	EffectiveIoLimits(context.Context, int) (*DiskIoLimits, *Response, error)
 }
 
 // DisksServiceOp handles communication with the Disk related methods of the
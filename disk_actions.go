@@ -0,0 +1,381 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const diskMigrateBasePath string = "virtual_machines/%d/disks/%d/migrate"
+const diskAutobackupEnableBasePath string = disksBasePath + "/%d/autobackup_enable"
+const diskAutobackupDisableBasePath string = disksBasePath + "/%d/autobackup_disable"
+
+// Actions of the Disk transactions
+const (
+	diskResizeAction  = "resize_disk"
+	diskMigrateAction = "migrate_disk"
+)
+
+// DiskResizeOptions -
+type DiskResizeOptions struct {
+	// Shrinking of the disk can destroy data, so it's refused by default
+	AllowShrink bool
+
+	// Wait for the transaction to finish if not nil
+	Wait *TransactionWaitOptions
+}
+
+// DiskMigrateOptions -
+type DiskMigrateOptions struct {
+	// Wait for the transaction to finish if not nil
+	Wait *TransactionWaitOptions
+}
+
+type diskResizeRequest struct {
+	DiskSize int `json:"disk_size"`
+}
+
+type diskResizeRequestRoot struct {
+	Disk *diskResizeRequest `json:"disk"`
+}
+
+// DiskIoLimits - IO limits of the DataStore with IOPS limits of the Disk,
+// MaxIops and BurstIops default to the ones of the DataStoreGroup
+type DiskIoLimits struct {
+	IoLimits
+
+	MaxIops   int
+	BurstIops int
+}
+
+type diskIoLimitsRequest struct {
+	IoLimitsOverride bool      `json:"io_limits_override"`
+	IoLimits         *IoLimits `json:"io_limits,omitempty"`
+	MaxIops          int       `json:"max_iops,omitempty"`
+	BurstIops        int       `json:"burst_iops,omitempty"`
+}
+
+type diskIoLimitsRequestRoot struct {
+	Disk *diskIoLimitsRequest `json:"disk"`
+}
+
+type diskMigrateRequest struct {
+	DataStoreID int `json:"data_store_id"`
+}
+
+type diskMigrateRequestRoot struct {
+	Disk *diskMigrateRequest `json:"disk"`
+}
+
+// FreeSpace return free space of the DataStore in GB, -1 if size is unknown
+func (obj *DataStore) FreeSpace() int {
+	if obj.DataStoreSize == 0 {
+		return -1
+	}
+
+	return obj.DataStoreSize - obj.Usage
+}
+
+// Resize Disk to the newSize GB and return transaction of resizing, finished
+// one if opts.Wait is set
+func (s *DisksServiceOp) Resize(ctx context.Context, id int, newSize int, opts *DiskResizeOptions) (*Transaction, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	if newSize < 1 {
+		return nil, nil, godo.NewArgError("newSize", "cannot be less than 1")
+	}
+
+	disk, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if newSize == disk.DiskSize {
+		return nil, resp, godo.NewArgError("newSize", fmt.Sprintf("Disk [%d] already has size %d GB", id, newSize))
+	}
+
+	if newSize < disk.DiskSize && (opts == nil || !opts.AllowShrink) {
+		return nil, resp, godo.NewArgError("newSize",
+			fmt.Sprintf("shrinking of Disk [%d] from %d GB to %d GB is not allowed", id, disk.DiskSize, newSize))
+	}
+
+	if newSize > disk.DiskSize {
+		resp, err = s.checkFreeSpace(ctx, disk.DataStoreID, newSize-disk.DiskSize)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	path := fmt.Sprintf("%s/%d%s", disksBasePath, id, apiFormat)
+	rootRequest := &diskResizeRequestRoot{
+		Disk: &diskResizeRequest{DiskSize: newSize},
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("Disk [Resize]  req: ", req)
+
+	start := time.Now()
+	resp, err = s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var wait *TransactionWaitOptions
+	if opts != nil {
+		wait = opts.Wait
+	}
+
+	return s.transaction(ctx, id, serverTime(resp, start), diskResizeAction, wait)
+}
+
+// Migrate Disk to the DataStore and return transaction of migration, finished
+// one if opts.Wait is set. Disk must be attached to a VirtualMachine, the
+// DataStore must be joined to the Hypervisor or HypervisorGroup of the
+// VirtualMachine and have enough free space for the Disk.
+func (s *DisksServiceOp) Migrate(ctx context.Context, id int, dataStoreID int, opts *DiskMigrateOptions) (*Transaction, *Response, error) {
+	if id < 1 || dataStoreID < 1 {
+		return nil, nil, godo.NewArgError("id || dataStoreID", "cannot be less than 1")
+	}
+
+	disk, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if disk.DataStoreID == dataStoreID {
+		return nil, resp, godo.NewArgError("dataStoreID", fmt.Sprintf("Disk [%d] already placed on DataStore [%d]", id, dataStoreID))
+	}
+
+	if disk.VirtualMachineID == 0 {
+		return nil, resp, fmt.Errorf("Disk [%d] is not attached to a VirtualMachine, only attached disks can be migrated", id)
+	}
+
+	vm, resp, err := s.client.VirtualMachines.Get(ctx, disk.VirtualMachineID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp, err = s.checkDataStoreJoined(ctx, vm.HypervisorID, dataStoreID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp, err = s.checkFreeSpace(ctx, dataStoreID, disk.DiskSize)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	path := fmt.Sprintf(diskMigrateBasePath, vm.ID, id) + apiFormat
+	rootRequest := &diskMigrateRequestRoot{
+		Disk: &diskMigrateRequest{DataStoreID: dataStoreID},
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("Disk [Migrate]  req: ", req)
+
+	start := time.Now()
+	resp, err = s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var wait *TransactionWaitOptions
+	if opts != nil {
+		wait = opts.Wait
+	}
+
+	return s.transaction(ctx, id, serverTime(resp, start), diskMigrateAction, wait)
+}
+
+// EnableAutobackup enable automatic backups for the Disk
+func (s *DisksServiceOp) EnableAutobackup(ctx context.Context, id int) (*Response, error) {
+	return s.autobackup(ctx, id, diskAutobackupEnableBasePath)
+}
+
+// DisableAutobackup disable automatic backups for the Disk
+func (s *DisksServiceOp) DisableAutobackup(ctx context.Context, id int) (*Response, error) {
+	return s.autobackup(ctx, id, diskAutobackupDisableBasePath)
+}
+
+func (s *DisksServiceOp) autobackup(ctx context.Context, id int, basePath string) (*Response, error) {
+	if id < 1 {
+		return nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(basePath, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Disk [Autobackup]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// SetIoLimits override IO limits of the DataStore for the Disk. Zero fields
+// of limits are inherited from the DataStore and its DataStoreGroup. Nil
+// limits remove the override, so the Disk use IO limits of the DataStore
+// again.
+func (s *DisksServiceOp) SetIoLimits(ctx context.Context, id int, limits *DiskIoLimits) (*Response, error) {
+	if id < 1 {
+		return nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf("%s/%d%s", disksBasePath, id, apiFormat)
+	rootRequest := &diskIoLimitsRequestRoot{
+		Disk: &diskIoLimitsRequest{
+			IoLimitsOverride: limits != nil,
+		},
+	}
+	if limits != nil {
+		rootRequest.Disk.IoLimits = &limits.IoLimits
+		rootRequest.Disk.MaxIops = limits.MaxIops
+		rootRequest.Disk.BurstIops = limits.BurstIops
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Disk [SetIoLimits]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// EffectiveIoLimits return IO limits applied to the Disk
+func (s *DisksServiceOp) EffectiveIoLimits(ctx context.Context, id int) (*DiskIoLimits, *Response, error) {
+	disk, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ds, resp, err := s.client.DataStores.Get(ctx, disk.DataStoreID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var group *DataStoreGroup
+	if ds.DataStoreGroupID > 0 {
+		group, resp, err = s.client.DataStoreGroups.Get(ctx, ds.DataStoreGroupID)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	limits := disk.EffectiveIoLimits(ds, group)
+
+	return &limits, resp, err
+}
+
+// EffectiveIoLimits merge IO limits of the Disk with the IO limits of its
+// DataStore and default IOPS limits of the DataStoreGroup. Without override
+// the parent limits are used as is, with override every not set limit of the
+// Disk is inherited from the parent.
+func (obj *Disk) EffectiveIoLimits(ds *DataStore, group *DataStoreGroup) DiskIoLimits {
+	var parent DiskIoLimits
+	if ds != nil {
+		parent.IoLimits = ds.IoLimits
+	}
+	if group != nil {
+		parent.MaxIops = group.DefaultMaxIops
+		parent.BurstIops = group.DefaultBurstIops
+	}
+
+	if !obj.IoLimitsOverride {
+		return parent
+	}
+
+	res := DiskIoLimits{IoLimits: obj.IoLimits, MaxIops: obj.MaxIops, BurstIops: obj.BurstIops}
+	if res.ReadIops == 0 {
+		res.ReadIops = parent.ReadIops
+	}
+	if res.WriteIops == 0 {
+		res.WriteIops = parent.WriteIops
+	}
+	if res.ReadThroughput == 0 {
+		res.ReadThroughput = parent.ReadThroughput
+	}
+	if res.WriteThroughput == 0 {
+		res.WriteThroughput = parent.WriteThroughput
+	}
+	if res.MaxIops == 0 {
+		res.MaxIops = parent.MaxIops
+	}
+	if res.BurstIops == 0 {
+		res.BurstIops = parent.BurstIops
+	}
+
+	return res
+}
+
+func (s *DisksServiceOp) checkFreeSpace(ctx context.Context, dataStoreID int, size int) (*Response, error) {
+	ds, resp, err := s.client.DataStores.Get(ctx, dataStoreID)
+	if err != nil {
+		return resp, err
+	}
+
+	free := ds.FreeSpace()
+	if free >= 0 && free < size {
+		return resp, fmt.Errorf("DataStore [%d] has %d GB of free space, but %d GB required", dataStoreID, free, size)
+	}
+
+	return resp, nil
+}
+
+// checkDataStoreJoined check if DataStore is joined to the Hypervisor or to
+// the HypervisorGroup of the Hypervisor
+func (s *DisksServiceOp) checkDataStoreJoined(ctx context.Context, hypervisorID int, dataStoreID int) (*Response, error) {
+	hv, resp, err := s.client.Hypervisors.Get(ctx, hypervisorID)
+	if err != nil {
+		return resp, err
+	}
+
+	targets := []JoinTarget{HypervisorJoinTarget(hv.ID)}
+	if hv.HypervisorGroupID > 0 {
+		targets = append(targets, HypervisorGroupJoinTarget(hv.HypervisorGroupID))
+	}
+
+	for _, target := range targets {
+		joins, resp, err := s.client.DataStoreJoins.ListByTarget(ctx, target, nil)
+		if err != nil {
+			return resp, err
+		}
+
+		for _, join := range joins {
+			if join.DataStoreID == dataStoreID {
+				return resp, nil
+			}
+		}
+	}
+
+	return resp, fmt.Errorf("DataStore [%d] is not joined to the Hypervisor [%d] or HypervisorGroup [%d]",
+		dataStoreID, hv.ID, hv.HypervisorGroupID)
+}
+
+// transaction return transaction of the action on the Disk created after
+// since, wait for it to finish if wait is not nil
+func (s *DisksServiceOp) transaction(ctx context.Context, id int, since time.Time, action string, wait *TransactionWaitOptions) (*Transaction, *Response, error) {
+	trx, resp, err := transactionSince(ctx, s.client, "Disk", id, since, action)
+	if err != nil || wait == nil {
+		return trx, resp, err
+	}
+
+	trxs, resp, err := s.client.Transactions.Wait(ctx, []Transaction{*trx}, wait)
+	if len(trxs) > 0 {
+		trx = &trxs[0]
+	}
+
+	return trx, resp, err
+}
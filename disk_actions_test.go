@@ -0,0 +1,285 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupDisk serve the Disk 1 placed on the DataStore 5 with 15 GB free and
+// return bodies of the PUT requests to the Disk
+func setupDisk(t *testing.T, disk Disk) *[]string {
+	var bodies []string
+
+	mux.HandleFunc("/settings/disks/1.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		testMethod(t, r, http.MethodGet)
+		json.NewEncoder(w).Encode(map[string]Disk{"disk": disk})
+	})
+
+	mux.HandleFunc("/settings/data_stores/5.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]DataStore{"data_store": {ID: 5, DataStoreSize: 100, Usage: 85}})
+	})
+
+	return &bodies
+}
+
+// setupDiskTransactions serve older transaction of the action, newer one of
+// the other action and the one created now with ID 30
+func setupDiskTransactions(action string) {
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().UTC().Format(time.RFC3339)
+		json.NewEncoder(w).Encode([]map[string]Transaction{
+			{"transaction": {ID: 31, Action: "build_disk", AssociatedObjectType: "Disk", AssociatedObjectID: 1, CreatedAt: now}},
+			{"transaction": {ID: 30, Action: action, AssociatedObjectType: "Disk", AssociatedObjectID: 1, CreatedAt: now, Status: TransactionPending}},
+			{"transaction": {ID: 20, Action: action, AssociatedObjectType: "Disk", AssociatedObjectID: 1, CreatedAt: "2020-01-01T00:00:00Z", Status: TransactionComplete}},
+		})
+	})
+
+	mux.HandleFunc("/transactions/30.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]Transaction{"transaction": {ID: 30, Action: action, Status: TransactionComplete}})
+	})
+}
+
+func TestDisks_ResizeUp(t *testing.T) {
+	setup()
+	defer teardown()
+
+	bodies := setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 5})
+	setupDiskTransactions(diskResizeAction)
+
+	trx, _, err := client.Disks.Resize(ctx, 1, 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, 30, trx.ID)
+	require.True(t, trx.Pending())
+	require.JSONEq(t, `{"disk":{"disk_size":20}}`, (*bodies)[0])
+
+	trx, _, err = client.Disks.Resize(ctx, 1, 25, &DiskResizeOptions{Wait: &TransactionWaitOptions{Interval: time.Millisecond}})
+	require.NoError(t, err)
+	require.True(t, trx.Complete())
+
+	_, _, err = client.Disks.Resize(ctx, 1, 26, nil)
+	require.Error(t, err, "DataStore has only 15 GB free")
+	require.Len(t, *bodies, 2)
+}
+
+func TestDisks_ResizeDown(t *testing.T) {
+	setup()
+	defer teardown()
+
+	bodies := setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 5})
+	setupDiskTransactions(diskResizeAction)
+
+	_, _, err := client.Disks.Resize(ctx, 1, 5, nil)
+	require.Error(t, err)
+
+	_, _, err = client.Disks.Resize(ctx, 1, 10, nil)
+	require.Error(t, err)
+	require.Empty(t, *bodies)
+
+	trx, _, err := client.Disks.Resize(ctx, 1, 5, &DiskResizeOptions{AllowShrink: true})
+	require.NoError(t, err)
+	require.Equal(t, 30, trx.ID)
+	require.JSONEq(t, `{"disk":{"disk_size":5}}`, (*bodies)[0])
+}
+
+func TestDisks_ResizeNoTransaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 5})
+	setupDiskTransactions("other")
+
+	trx, _, err := client.Disks.Resize(ctx, 1, 20, nil)
+	require.Error(t, err)
+	require.Nil(t, trx)
+}
+
+func TestDisks_MigrateSameDataStore(t *testing.T) {
+	setup()
+	defer teardown()
+
+	setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 5, VirtualMachineID: 7})
+
+	_, _, err := client.Disks.Migrate(ctx, 1, 5, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already placed")
+}
+
+func TestDisks_MigrateDetached(t *testing.T) {
+	setup()
+	defer teardown()
+
+	setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 5})
+
+	_, _, err := client.Disks.Migrate(ctx, 1, 6, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not attached")
+}
+
+func TestDisks_Migrate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	setupDisk(t, Disk{ID: 1, DiskSize: 10, DataStoreID: 6, VirtualMachineID: 7})
+	setupDiskTransactions(diskMigrateAction)
+
+	mux.HandleFunc("/virtual_machines/7.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]VirtualMachine{"virtual_machine": {ID: 7, HypervisorID: 3}})
+	})
+
+	mux.HandleFunc("/settings/hypervisors/3.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]Hypervisor{"hypervisor": {ID: 3, HypervisorGroupID: 4}})
+	})
+
+	mux.HandleFunc("/settings/hypervisors/3/data_store_joins.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]DataStoreJoin{})
+	})
+
+	mux.HandleFunc("/settings/hypervisor_zones/4/data_store_joins.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]DataStoreJoin{{"data_store_join": {ID: 1, DataStoreID: 5}}})
+	})
+
+	mux.HandleFunc("/virtual_machines/7/disks/1/migrate.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		require.JSONEq(t, `{"disk":{"data_store_id":5}}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	trx, _, err := client.Disks.Migrate(ctx, 1, 5, &DiskMigrateOptions{Wait: &TransactionWaitOptions{Interval: time.Millisecond}})
+	require.NoError(t, err)
+	require.Equal(t, 30, trx.ID)
+	require.True(t, trx.Complete())
+}
+
+func TestDisk_EffectiveIoLimits(t *testing.T) {
+	ds := &DataStore{IoLimits: IoLimits{ReadIops: 100, WriteIops: 200, ReadThroughput: 300, WriteThroughput: 400}}
+	group := &DataStoreGroup{DefaultMaxIops: 500, DefaultBurstIops: 600}
+	parent := DiskIoLimits{IoLimits: ds.IoLimits, MaxIops: 500, BurstIops: 600}
+
+	tests := []struct {
+		name  string
+		disk  Disk
+		ds    *DataStore
+		group *DataStoreGroup
+		want  DiskIoLimits
+	}{
+		{
+			name:  "without override",
+			disk:  Disk{IoLimits: IoLimits{ReadIops: 1}, MaxIops: 5, BurstIops: 6},
+			ds:    ds,
+			group: group,
+			want:  parent,
+		},
+		{
+			name:  "partial override",
+			disk:  Disk{IoLimitsOverride: true, IoLimits: IoLimits{ReadIops: 1, WriteThroughput: 4}, BurstIops: 6},
+			ds:    ds,
+			group: group,
+			want:  DiskIoLimits{IoLimits: IoLimits{ReadIops: 1, WriteIops: 200, ReadThroughput: 300, WriteThroughput: 4}, MaxIops: 500, BurstIops: 6},
+		},
+		{
+			name:  "full override",
+			disk:  Disk{IoLimitsOverride: true, IoLimits: IoLimits{ReadIops: 1, WriteIops: 2, ReadThroughput: 3, WriteThroughput: 4}, MaxIops: 5, BurstIops: 6},
+			ds:    ds,
+			group: group,
+			want:  DiskIoLimits{IoLimits: IoLimits{ReadIops: 1, WriteIops: 2, ReadThroughput: 3, WriteThroughput: 4}, MaxIops: 5, BurstIops: 6},
+		},
+		{
+			name: "without data store group",
+			disk: Disk{IoLimitsOverride: true, IoLimits: IoLimits{ReadIops: 1}, MaxIops: 5},
+			ds:   ds,
+			want: DiskIoLimits{IoLimits: IoLimits{ReadIops: 1, WriteIops: 200, ReadThroughput: 300, WriteThroughput: 400}, MaxIops: 5},
+		},
+		{
+			name: "unknown data store",
+			disk: Disk{IoLimitsOverride: true, IoLimits: IoLimits{ReadIops: 1}},
+			want: DiskIoLimits{IoLimits: IoLimits{ReadIops: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.disk.EffectiveIoLimits(tt.ds, tt.group))
+		})
+	}
+}
+
+func TestDisks_Autobackup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := map[string]int{}
+	for _, action := range []string{"autobackup_enable", "autobackup_disable"} {
+		action := action
+		mux.HandleFunc("/settings/disks/1/"+action+".json", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			calls[action]++
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+
+	_, err := client.Disks.EnableAutobackup(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = client.Disks.DisableAutobackup(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = client.Disks.EnableAutobackup(ctx, 0)
+	require.Error(t, err)
+
+	require.Equal(t, map[string]int{"autobackup_enable": 1, "autobackup_disable": 1}, calls)
+}
+
+func TestDisks_SetIoLimits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	bodies := setupDisk(t, Disk{ID: 1, DataStoreID: 5})
+
+	_, err := client.Disks.SetIoLimits(ctx, 1, &DiskIoLimits{IoLimits: IoLimits{ReadIops: 10}})
+	require.NoError(t, err)
+
+	_, err = client.Disks.SetIoLimits(ctx, 1, &DiskIoLimits{MaxIops: 50, BurstIops: 80})
+	require.NoError(t, err)
+
+	_, err = client.Disks.SetIoLimits(ctx, 1, nil)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{"disk":{"io_limits_override":true,"io_limits":{"read_iops":10}}}`, (*bodies)[0])
+	require.JSONEq(t, `{"disk":{"io_limits_override":true,"io_limits":{},"max_iops":50,"burst_iops":80}}`, (*bodies)[1])
+	require.JSONEq(t, `{"disk":{"io_limits_override":false}}`, (*bodies)[2])
+}
+
+func TestDisks_EffectiveIoLimits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/disks/1.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]Disk{"disk": {ID: 1, DataStoreID: 5, IoLimitsOverride: true, IoLimits: IoLimits{ReadIops: 10}, BurstIops: 80}})
+	})
+
+	mux.HandleFunc("/settings/data_stores/5.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]DataStore{"data_store": {ID: 5, DataStoreGroupID: 2, IoLimits: IoLimits{ReadIops: 100, WriteIops: 200}}})
+	})
+
+	mux.HandleFunc("/settings/data_store_zones/2.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]DataStoreGroup{"data_store_group": {ID: 2, DefaultMaxIops: 50, DefaultBurstIops: 60}})
+	})
+
+	limits, _, err := client.Disks.EffectiveIoLimits(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, &DiskIoLimits{IoLimits: IoLimits{ReadIops: 10, WriteIops: 200}, MaxIops: 50, BurstIops: 80}, limits)
+}
@@ -20,6 +20,10 @@ type FirewallRulesService interface {
 	Create(context.Context, int, *FirewallRuleCreateRequest) (*FirewallRule, *Response, error)
 	Delete(context.Context, int, int, interface{}) (*Response, error)
 	Edit(context.Context, int, int, *FirewallRuleCreateRequest) (*Response, error)
+
+	Reconcile(context.Context, int, FirewallRuleSet) (*FirewallReconcileReport, *Response, error)
+	Move(context.Context, int, int, *FirewallRuleMoveRequest) (*Response, error)
+	SetDefaultPolicy(context.Context, int, int, string) (*Response, error)
 }
 
 // FirewallRulesServiceOp handles communication with the FirewallRules related methods of the
@@ -61,6 +65,9 @@ type FirewallRuleCreateRequest struct {
 	NetworkInterfaceID int    `json:"network_interface_id,omitempty"`
 	Comment            string `json:"comment,omitempty"`
 	Port               string `json:"port,omitempty"`
+	Position           int    `json:"position,omitempty"`
+	SourcePort         string `json:"source_port,omitempty"`
+	DestinationIP      string `json:"destination_ip,omitempty"`
 }
 
 type firewallRuleCreateRequestRoot struct {
@@ -134,6 +141,10 @@ func (s *FirewallRulesServiceOp) Create(ctx context.Context, vmID int, createReq
 		return nil, nil, godo.NewArgError("FirewallRule createRequest", "cannot be nil")
 	}
 
+	if err := createRequest.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	path := fmt.Sprintf(firewallRulesBasePath, vmID) + apiFormat
 	rootRequest := &firewallRuleCreateRequestRoot{
 		FirewallRuleCreateRequest: createRequest,
@@ -186,10 +197,17 @@ func (s *FirewallRulesServiceOp) Edit(ctx context.Context, vmID int, id int, edi
 		return nil, godo.NewArgError("FirewallRule [Edit] editRequest", "cannot be nil")
 	}
 
+	if err := editRequest.ValidateEdit(); err != nil {
+		return nil, err
+	}
+
 	path := fmt.Sprintf(firewallRulesBasePath, vmID)
 	path = fmt.Sprintf("%s/%d%s", path, id, apiFormat)
+	rootRequest := &firewallRuleCreateRequestRoot{
+		FirewallRuleCreateRequest: editRequest,
+	}
 
-	req, err := s.client.NewRequest(ctx, http.MethodPut, path, editRequest)
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
 	if err != nil {
 		return nil, err
 	}
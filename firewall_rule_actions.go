@@ -0,0 +1,294 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+const firewallRuleMoveBasePath string = firewallRulesBasePath + "/%d/move"
+const firewallRulesDefaultsBasePath string = firewallRulesBasePath + "/update_defaults"
+
+// Firewall commands
+const (
+	FirewallAccept = "ACCEPT"
+	FirewallDrop   = "DROP"
+)
+
+// Firewall protocols
+const (
+	FirewallProtocolTCP  = "TCP"
+	FirewallProtocolUDP  = "UDP"
+	FirewallProtocolICMP = "ICMP"
+)
+
+// Firewall rule move directions
+const (
+	FirewallRuleMoveUp   = "up"
+	FirewallRuleMoveDown = "down"
+)
+
+const (
+	maxFirewallPort     = 65535
+	maxFirewallICMPType = 255
+)
+
+// FirewallRuleMoveRequest - move rule one step in the Direction or to the
+// Position among rules of the same NetworkInterface
+type FirewallRuleMoveRequest struct {
+	Direction string
+	Position  int
+}
+
+type firewallRuleMoveRequestRoot struct {
+	Position string `json:"position"`
+}
+
+type firewallRuleDefaults struct {
+	DefaultFirewallRule string `json:"default_firewall_rule"`
+}
+
+type firewallRuleDefaultsRoot struct {
+	NetworkInterfaces map[string]firewallRuleDefaults `json:"network_interfaces"`
+}
+
+// Validate FirewallRuleCreateRequest before sending it to the OnApp API.
+// Ports are set as comma separated list of ports and ranges "from:to", ICMP
+// rules take ICMP type in the Port. Returned error is *ValidationError.
+func (d *FirewallRuleCreateRequest) Validate() error {
+	return d.validate(false)
+}
+
+// ValidateEdit validate only fields set in the request used to edit the
+// existing FirewallRule. Ports are not checked without the Protocol.
+func (d *FirewallRuleCreateRequest) ValidateEdit() error {
+	return d.validate(true)
+}
+
+// validate the request, partial one is checked only by the set fields
+func (d *FirewallRuleCreateRequest) validate(partial bool) error {
+	verr := &ValidationError{}
+
+	if d.NetworkInterfaceID < 1 && (!partial || d.NetworkInterfaceID != 0) {
+		verr.Add("network_interface_id", "cannot be less than 1")
+	}
+
+	command := strings.ToUpper(d.Command)
+	switch {
+	case partial && command == "":
+	case command == FirewallAccept, command == FirewallDrop:
+	default:
+		verr.Add("command", "%q is not supported, use %s or %s", d.Command, FirewallAccept, FirewallDrop)
+	}
+
+	protocol := strings.ToUpper(d.Protocol)
+	switch {
+	case partial && protocol == "":
+	case protocol == FirewallProtocolTCP, protocol == FirewallProtocolUDP:
+		if err := validateFirewallPorts(d.Port); err != nil {
+			verr.Add("port", "%s", err)
+		}
+		if err := validateFirewallPorts(d.SourcePort); err != nil {
+			verr.Add("source_port", "%s", err)
+		}
+	case protocol == FirewallProtocolICMP:
+		if d.SourcePort != "" {
+			verr.Add("source_port", "cannot be set for %s", FirewallProtocolICMP)
+		}
+		if d.Port != "" {
+			if t, err := strconv.Atoi(d.Port); err != nil || t < 0 || t > maxFirewallICMPType {
+				verr.Add("port", "%q is not an ICMP type, should be 0-%d", d.Port, maxFirewallICMPType)
+			}
+		}
+	default:
+		verr.Add("protocol", "%q is not supported, use %s, %s or %s", d.Protocol,
+			FirewallProtocolTCP, FirewallProtocolUDP, FirewallProtocolICMP)
+	}
+
+	if d.Address != "" && net.ParseIP(d.Address) == nil {
+		if _, _, err := net.ParseCIDR(d.Address); err != nil {
+			verr.Add("address", "%q is neither IP address nor CIDR", d.Address)
+		}
+	}
+
+	if d.DestinationIP != "" && net.ParseIP(d.DestinationIP) == nil {
+		if _, _, err := net.ParseCIDR(d.DestinationIP); err != nil {
+			verr.Add("destination_ip", "%q is neither IP address nor CIDR", d.DestinationIP)
+		}
+	}
+
+	return verr.errOrNil()
+}
+
+func validateFirewallPorts(ports string) error {
+	if ports == "" {
+		return nil
+	}
+
+	for _, item := range strings.Split(ports, ",") {
+		bounds := strings.Split(strings.TrimSpace(item), ":")
+		if len(bounds) > 2 {
+			return fmt.Errorf("%q is not a port range, should be from:to", item)
+		}
+
+		var values []int
+		for _, b := range bounds {
+			p, err := strconv.Atoi(b)
+			if err != nil || p < 1 || p > maxFirewallPort {
+				return fmt.Errorf("%q is not a port, should be 1-%d", b, maxFirewallPort)
+			}
+			values = append(values, p)
+		}
+
+		if len(values) == 2 && values[0] > values[1] {
+			return fmt.Errorf("port range %q is reversed", item)
+		}
+	}
+
+	return nil
+}
+
+// Move FirewallRule one step up or down, or to the position among rules of
+// the same NetworkInterface
+func (s *FirewallRulesServiceOp) Move(ctx context.Context, vmID int, id int, moveRequest *FirewallRuleMoveRequest) (*Response, error) {
+	if vmID < 1 || id < 1 {
+		return nil, godo.NewArgError("vmID || id", "cannot be less than 1")
+	}
+
+	if moveRequest == nil {
+		return nil, godo.NewArgError("FirewallRule moveRequest", "cannot be nil")
+	}
+
+	if moveRequest.Direction != "" && moveRequest.Position != 0 {
+		return nil, godo.NewArgError("FirewallRule moveRequest", "only one of Direction or Position can be set")
+	}
+
+	switch moveRequest.Direction {
+	case FirewallRuleMoveUp, FirewallRuleMoveDown:
+		return s.move(ctx, vmID, id, moveRequest.Direction)
+	case "":
+	default:
+		return nil, godo.NewArgError("Direction", fmt.Sprintf("%q is not supported, use %q or %q",
+			moveRequest.Direction, FirewallRuleMoveUp, FirewallRuleMoveDown))
+	}
+
+	if moveRequest.Position < 1 {
+		return nil, godo.NewArgError("Position", "cannot be less than 1")
+	}
+
+	// positions of rules may have gaps, so the rule is moved by steps until
+	// it takes the place among rules of its NetworkInterface
+	var resp *Response
+	for step := 0; ; step++ {
+		index, count, r, err := s.ruleIndex(ctx, vmID, id)
+		if err != nil {
+			return r, err
+		}
+		resp = r
+
+		if moveRequest.Position > count {
+			return resp, godo.NewArgError("Position", fmt.Sprintf("cannot be greater than %d", count))
+		}
+
+		if index == moveRequest.Position {
+			return resp, nil
+		}
+
+		if step >= count {
+			return resp, fmt.Errorf("FirewallRule [%d] of the VirtualMachine [%d] is not moved to the position %d, it is at %d",
+				id, vmID, moveRequest.Position, index)
+		}
+
+		direction := FirewallRuleMoveUp
+		if index < moveRequest.Position {
+			direction = FirewallRuleMoveDown
+		}
+
+		resp, err = s.move(ctx, vmID, id, direction)
+		if err != nil {
+			return resp, err
+		}
+	}
+}
+
+// ruleIndex return place of the rule starting from 1 among rules of its
+// NetworkInterface ordered by position, and the number of these rules
+func (s *FirewallRulesServiceOp) ruleIndex(ctx context.Context, vmID int, id int) (int, int, *Response, error) {
+	lst, resp, err := s.listAll(ctx, vmID)
+	if err != nil {
+		return 0, 0, resp, err
+	}
+
+	var rule *FirewallRule
+	for i := range lst {
+		if lst[i].ID == id {
+			rule = &lst[i]
+		}
+	}
+
+	if rule == nil {
+		return 0, 0, resp, fmt.Errorf("FirewallRule [%d] of the VirtualMachine [%d] not found", id, vmID)
+	}
+
+	index, count := 1, 0
+	for _, v := range lst {
+		if v.NetworkInterfaceID != rule.NetworkInterfaceID {
+			continue
+		}
+		count++
+
+		if v.Position < rule.Position || v.Position == rule.Position && v.ID < rule.ID {
+			index++
+		}
+	}
+
+	return index, count, resp, nil
+}
+
+func (s *FirewallRulesServiceOp) move(ctx context.Context, vmID int, id int, direction string) (*Response, error) {
+	path := fmt.Sprintf(firewallRuleMoveBasePath, vmID, id) + apiFormat
+	rootRequest := &firewallRuleMoveRequestRoot{
+		Position: direction,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, rootRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("FirewallRule [Move]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// SetDefaultPolicy set default command of the firewall for the NetworkInterface
+func (s *FirewallRulesServiceOp) SetDefaultPolicy(ctx context.Context, vmID int, networkInterfaceID int, command string) (*Response, error) {
+	if vmID < 1 || networkInterfaceID < 1 {
+		return nil, godo.NewArgError("vmID || networkInterfaceID", "cannot be less than 1")
+	}
+
+	command = strings.ToUpper(command)
+	if command != FirewallAccept && command != FirewallDrop {
+		return nil, godo.NewArgError("command", fmt.Sprintf("%q is not supported, use %s or %s", command, FirewallAccept, FirewallDrop))
+	}
+
+	path := fmt.Sprintf(firewallRulesDefaultsBasePath, vmID) + apiFormat
+	rootRequest := &firewallRuleDefaultsRoot{
+		NetworkInterfaces: map[string]firewallRuleDefaults{
+			strconv.Itoa(networkInterfaceID): {DefaultFirewallRule: command},
+		},
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("FirewallRule [SetDefaultPolicy]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,213 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirewallRuleCreateRequest_Validate(t *testing.T) {
+	valid := []FirewallRuleCreateRequest{
+		{NetworkInterfaceID: 1, Command: "accept", Protocol: "tcp", Port: "22"},
+		{NetworkInterfaceID: 1, Command: "DROP", Protocol: "UDP", Port: "53,1000:2000", Address: "10.0.0.0/8"},
+		{NetworkInterfaceID: 1, Command: "DROP", Protocol: "ICMP", Port: "8", Address: "2001:db8::1"},
+		{NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP"},
+		{NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP", Port: "80", SourcePort: "1024:65535", DestinationIP: "192.168.0.0/16"},
+	}
+
+	for _, v := range valid {
+		require.NoError(t, v.Validate(), "%+v", v)
+	}
+
+	invalid := map[string]FirewallRuleCreateRequest{
+		"network_interface_id": {Command: "ACCEPT", Protocol: "TCP"},
+		"command":              {NetworkInterfaceID: 1, Command: "REJECT", Protocol: "TCP"},
+		"protocol":             {NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "GRE"},
+		"port":                 {NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP", Port: "70000"},
+		"address":              {NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP", Address: "10.0.0.0/33"},
+		"source_port":          {NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "ICMP", SourcePort: "22"},
+		"destination_ip":       {NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP", DestinationIP: "host"},
+	}
+
+	for field, v := range invalid {
+		err := v.Validate()
+		require.Error(t, err, field)
+		require.Contains(t, err.(*ValidationError).Errors, field)
+	}
+
+	for _, port := range []string{"2000:1000", "1:2:3", "0", "ssh"} {
+		require.Error(t, (&FirewallRuleCreateRequest{NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "TCP", Port: port}).Validate(), port)
+	}
+
+	require.Error(t, (&FirewallRuleCreateRequest{NetworkInterfaceID: 1, Command: "ACCEPT", Protocol: "ICMP", Port: "256"}).Validate())
+}
+
+// setupFirewallRulesMoving serve rules of the VirtualMachine 1 and move
+// them by swapping positions with the neighbour of the same NetworkInterface,
+// return directions of the moves
+func setupFirewallRulesMoving(t *testing.T, rules []FirewallRule) *[]string {
+	mux.HandleFunc("/virtual_machines/1/firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		var items []interface{}
+		for _, v := range rules {
+			items = append(items, v)
+		}
+		writeTestPage(t, w, r, "firewall_rule", items)
+	})
+
+	var moves []string
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/virtual_machines/1/firewall_rules/"), "%d/move.json", &id)
+
+		var got firewallRuleMoveRequestRoot
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		moves = append(moves, got.Position)
+
+		var rule, neighbour *FirewallRule
+		for i := range rules {
+			if rules[i].ID == id {
+				rule = &rules[i]
+			}
+		}
+		require.NotNil(t, rule)
+
+		for i := range rules {
+			v := &rules[i]
+			if v.NetworkInterfaceID != rule.NetworkInterfaceID {
+				continue
+			}
+
+			if got.Position == FirewallRuleMoveUp && v.Position < rule.Position && (neighbour == nil || v.Position > neighbour.Position) ||
+				got.Position == FirewallRuleMoveDown && v.Position > rule.Position && (neighbour == nil || v.Position < neighbour.Position) {
+				neighbour = v
+			}
+		}
+
+		if neighbour != nil {
+			rule.Position, neighbour.Position = neighbour.Position, rule.Position
+		}
+	})
+
+	return &moves
+}
+
+func TestFirewallRules_MoveToPosition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// positions have gaps after deleted rules
+	moves := setupFirewallRulesMoving(t, []FirewallRule{
+		{ID: 1, NetworkInterfaceID: 5, Position: 2},
+		{ID: 2, NetworkInterfaceID: 5, Position: 5},
+		{ID: 3, NetworkInterfaceID: 5, Position: 9},
+		{ID: 4, NetworkInterfaceID: 6, Position: 1},
+	})
+
+	_, err := client.FirewallRules.Move(ctx, 1, 3, &FirewallRuleMoveRequest{Position: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{FirewallRuleMoveUp, FirewallRuleMoveUp}, *moves)
+
+	_, err = client.FirewallRules.Move(ctx, 1, 3, &FirewallRuleMoveRequest{Position: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{FirewallRuleMoveUp, FirewallRuleMoveUp, FirewallRuleMoveDown}, *moves)
+
+	_, err = client.FirewallRules.Move(ctx, 1, 3, &FirewallRuleMoveRequest{Position: 2})
+	require.NoError(t, err)
+	require.Len(t, *moves, 3)
+
+	_, err = client.FirewallRules.Move(ctx, 1, 3, &FirewallRuleMoveRequest{Position: 4})
+	require.Error(t, err)
+
+	_, err = client.FirewallRules.Move(ctx, 1, 3, &FirewallRuleMoveRequest{Direction: "left"})
+	require.Error(t, err)
+
+	_, err = client.FirewallRules.Move(ctx, 1, 7, &FirewallRuleMoveRequest{Position: 1})
+	require.Error(t, err)
+}
+
+func TestFirewallRules_MoveNotMoved(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"firewall_rule":{"id":1,"network_interface_id":5,"position":1}},
+			{"firewall_rule":{"id":2,"network_interface_id":5,"position":2}}]`)
+	})
+
+	moves := 0
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/2/move.json", func(w http.ResponseWriter, r *http.Request) {
+		moves++
+	})
+
+	_, err := client.FirewallRules.Move(ctx, 1, 2, &FirewallRuleMoveRequest{Position: 1})
+	require.Error(t, err)
+	require.Equal(t, 2, moves)
+}
+
+func TestFirewallRules_Edit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var bodies []string
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/3.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	})
+
+	_, err := client.FirewallRules.Edit(ctx, 1, 3, &FirewallRuleCreateRequest{Comment: "ssh"})
+	require.NoError(t, err)
+
+	_, err = client.FirewallRules.Edit(ctx, 1, 3, &FirewallRuleCreateRequest{Port: "2222"})
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	require.JSONEq(t, `{"firewall_rule":{"comment":"ssh"}}`, bodies[0])
+	require.JSONEq(t, `{"firewall_rule":{"port":"2222"}}`, bodies[1])
+
+	tests := []*FirewallRuleCreateRequest{
+		{Command: "REJECT"},
+		{Protocol: "SCTP"},
+		{Protocol: "TCP", Port: "70000"},
+		{Protocol: "ICMP", SourcePort: "22"},
+		{Address: "10.0.0.1/99"},
+		{NetworkInterfaceID: -1},
+	}
+
+	for _, v := range tests {
+		require.Error(t, v.ValidateEdit(), "%+v", v)
+		_, err = client.FirewallRules.Edit(ctx, 1, 3, v)
+		require.Error(t, err)
+	}
+	require.Len(t, bodies, 2)
+
+	require.Error(t, (&FirewallRuleCreateRequest{Comment: "ssh"}).Validate())
+}
+
+func TestFirewallRules_SetDefaultPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/update_defaults.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		var got firewallRuleDefaultsRoot
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, FirewallDrop, got.NetworkInterfaces["5"].DefaultFirewallRule)
+	})
+
+	_, err := client.FirewallRules.SetDefaultPolicy(ctx, 1, 5, "drop")
+	require.NoError(t, err)
+
+	_, err = client.FirewallRules.SetDefaultPolicy(ctx, 1, 5, "REJECT")
+	require.Error(t, err)
+}
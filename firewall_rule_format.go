@@ -0,0 +1,423 @@
+package onappgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// firewallChainPrefix - iptables chain of the NetworkInterface rules is named
+// by the prefix and NetworkInterface ID, e.g. "onapp-ni-5"
+const firewallChainPrefix string = "onapp-ni-"
+
+// EncodeFirewallRulesIptables write rules in the iptables-save format. Rules
+// of every NetworkInterface are written to its own chain ordered by Position,
+// so decoded rules get the same Position. ICMP rules write the Port as ICMP
+// type.
+func EncodeFirewallRulesIptables(w io.Writer, rules []FirewallRule) error {
+	var chains []string
+	seen := make(map[string]bool)
+	lines := make([]string, 0, len(rules))
+
+	for _, i := range firewallRulesOrder(rules) {
+		line, err := encodeIptablesRule(&rules[i])
+		if err != nil {
+			return fmt.Errorf("rule %d: %s", i, err)
+		}
+		lines = append(lines, line)
+
+		chain := firewallChain(rules[i].NetworkInterfaceID)
+		if !seen[chain] {
+			seen[chain] = true
+			chains = append(chains, chain)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "*filter")
+	for _, chain := range chains {
+		fmt.Fprintf(bw, ":%s - [0:0]\n", chain)
+	}
+	for _, line := range lines {
+		fmt.Fprintln(bw, line)
+	}
+	fmt.Fprintln(bw, "COMMIT")
+
+	return bw.Flush()
+}
+
+// firewallRulesOrder return indexes of the rules ordered by NetworkInterface
+// and Position, rules with the same Position keep their order
+func firewallRulesOrder(rules []FirewallRule) []int {
+	res := make([]int, len(rules))
+	for i := range res {
+		res[i] = i
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := &rules[res[i]], &rules[res[j]]
+		if a.NetworkInterfaceID != b.NetworkInterfaceID {
+			return a.NetworkInterfaceID < b.NetworkInterfaceID
+		}
+		return a.Position < b.Position
+	})
+
+	return res
+}
+
+// checkFirewallCommand return error for commands which cannot be decoded
+func checkFirewallCommand(command string) error {
+	switch strings.ToUpper(command) {
+	case FirewallAccept, FirewallDrop:
+		return nil
+	}
+
+	return fmt.Errorf("Command %q is not supported, use %s or %s", command, FirewallAccept, FirewallDrop)
+}
+
+func firewallChain(networkInterfaceID int) string {
+	return firewallChainPrefix + strconv.Itoa(networkInterfaceID)
+}
+
+func encodeIptablesRule(rule *FirewallRule) (string, error) {
+	if rule.NetworkInterfaceID < 1 {
+		return "", fmt.Errorf("NetworkInterfaceID cannot be less than 1")
+	}
+
+	if err := checkFirewallCommand(rule.Command); err != nil {
+		return "", err
+	}
+
+	args := []string{"-A", firewallChain(rule.NetworkInterfaceID)}
+	if rule.Address != "" {
+		args = append(args, "-s", rule.Address)
+	}
+	if rule.DestinationIP != "" {
+		args = append(args, "-d", rule.DestinationIP)
+	}
+
+	protocol := strings.ToLower(rule.Protocol)
+	if protocol != "" {
+		args = append(args, "-p", protocol)
+	}
+
+	if protocol == "icmp" {
+		if rule.SourcePort != "" {
+			return "", fmt.Errorf("ICMP rule cannot have SourcePort")
+		}
+		if rule.Port != "" {
+			args = append(args, "-m", "icmp", "--icmp-type", rule.Port)
+		}
+	} else {
+		if (rule.Port != "" || rule.SourcePort != "") && protocol != "tcp" && protocol != "udp" {
+			return "", fmt.Errorf("ports are supported only for TCP and UDP, got protocol %q", rule.Protocol)
+		}
+
+		args = appendIptablesPorts(args, protocol, "--sport", rule.SourcePort)
+		args = appendIptablesPorts(args, protocol, "--dport", rule.Port)
+	}
+
+	if rule.Comment != "" {
+		args = append(args, "-m", "comment", "--comment", strconv.Quote(rule.Comment))
+	}
+
+	args = append(args, "-j", strings.ToUpper(rule.Command))
+
+	for _, v := range args {
+		if v == "" || (strings.ContainsAny(v, " \t\n") && !strings.HasPrefix(v, `"`)) {
+			return "", fmt.Errorf("value %q cannot be written to the iptables rule", v)
+		}
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+func appendIptablesPorts(args []string, protocol string, option string, ports string) []string {
+	if ports == "" {
+		return args
+	}
+
+	if strings.Contains(ports, ",") {
+		return append(args, "-m", "multiport", option+"s", ports)
+	}
+
+	return append(args, "-m", protocol, option, ports)
+}
+
+// DecodeFirewallRulesIptables read rules written in the iptables-save format
+// by EncodeFirewallRulesIptables. Only filter table, chains of the
+// NetworkInterfaces and matches produced by the encoder are supported, any
+// other construct is reported as error with the line number.
+func DecodeFirewallRulesIptables(r io.Reader) ([]FirewallRule, error) {
+	var res []FirewallRule
+	positions := make(map[int]int)
+	table := ""
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "*"):
+			table = line[1:]
+			if table != "filter" {
+				return nil, fmt.Errorf("line %d: table %q is not supported, only filter", n, table)
+			}
+		case line == "COMMIT":
+			table = ""
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(line[1:])
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("line %d: chain has no name", n)
+			}
+			if _, err := parseFirewallChain(fields[0]); err != nil {
+				return nil, fmt.Errorf("line %d: %s", n, err)
+			}
+		case strings.HasPrefix(line, "-A "):
+			if table == "" {
+				return nil, fmt.Errorf("line %d: rule outside of the table", n)
+			}
+
+			rule, err := decodeIptablesRule(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", n, err)
+			}
+
+			positions[rule.NetworkInterfaceID]++
+			rule.Position = positions[rule.NetworkInterfaceID]
+			res = append(res, *rule)
+		default:
+			return nil, fmt.Errorf("line %d: unsupported statement %q", n, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if table != "" {
+		return nil, fmt.Errorf("table %q is not committed", table)
+	}
+
+	return res, nil
+}
+
+func parseFirewallChain(chain string) (int, error) {
+	if !strings.HasPrefix(chain, firewallChainPrefix) {
+		return 0, fmt.Errorf("chain %q is not supported, expected %s<NetworkInterfaceID>", chain, firewallChainPrefix)
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(chain, firewallChainPrefix))
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("chain %q has wrong NetworkInterface ID", chain)
+	}
+
+	return id, nil
+}
+
+func decodeIptablesRule(line string) (*FirewallRule, error) {
+	args, err := splitIptablesArgs(line)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &FirewallRule{}
+	next := func(i int) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("option %s has no value", args[i])
+		}
+		return args[i+1], nil
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		value, err := next(i)
+		if err != nil {
+			return nil, err
+		}
+
+		switch args[i] {
+		case "-A":
+			rule.NetworkInterfaceID, err = parseFirewallChain(value)
+		case "-s":
+			rule.Address = value
+		case "-d":
+			rule.DestinationIP = value
+		case "-p":
+			rule.Protocol = strings.ToUpper(value)
+		case "-m":
+			switch value {
+			case "tcp", "udp", "icmp", "multiport", "comment":
+			default:
+				err = fmt.Errorf("match %q is not supported", value)
+			}
+		case "--dport", "--dports":
+			rule.Port = value
+		case "--sport", "--sports":
+			rule.SourcePort = value
+		case "--icmp-type":
+			rule.Port = value
+		case "--comment":
+			rule.Comment, err = strconv.Unquote(value)
+		case "-j":
+			rule.Command = value
+			switch value {
+			case FirewallAccept, FirewallDrop:
+			default:
+				err = fmt.Errorf("target %q is not supported, use %s or %s", value, FirewallAccept, FirewallDrop)
+			}
+		default:
+			err = fmt.Errorf("option %q is not supported", args[i])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Command == "" {
+		return nil, fmt.Errorf("rule has no target")
+	}
+
+	return rule, nil
+}
+
+// splitIptablesArgs split line by spaces, double quoted values are kept as
+// is with quotes to be unquoted later
+func splitIptablesArgs(line string) ([]string, error) {
+	var res []string
+
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '!' {
+			return nil, fmt.Errorf("negation is not supported")
+		}
+
+		if line[0] != '"' {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+			res = append(res, line[:end])
+			line = line[end:]
+			continue
+		}
+
+		end := 1
+		for ; end < len(line) && line[end] != '"'; end++ {
+			if line[end] == '\\' {
+				end++
+			}
+		}
+
+		if end >= len(line) {
+			return nil, fmt.Errorf("unterminated quoted value %s", line)
+		}
+
+		res = append(res, line[:end+1])
+		line = line[end+1:]
+	}
+
+	return res, nil
+}
+
+// firewallRuleYAML - YAML schema of the FirewallRule
+type firewallRuleYAML struct {
+	Interface     int    `yaml:"interface"`
+	Command       string `yaml:"command"`
+	Protocol      string `yaml:"protocol,omitempty"`
+	Address       string `yaml:"address,omitempty"`
+	DestinationIP string `yaml:"destination_ip,omitempty"`
+	Port          string `yaml:"port,omitempty"`
+	SourcePort    string `yaml:"source_port,omitempty"`
+	Comment       string `yaml:"comment,omitempty"`
+}
+
+type firewallRulesYAML struct {
+	Rules []firewallRuleYAML `yaml:"rules"`
+}
+
+// EncodeFirewallRulesYAML write rules as YAML document with the list of
+// rules ordered by NetworkInterface and Position, e.g.
+//
+//	rules:
+//	  - interface: 5
+//	    command: ACCEPT
+//	    protocol: TCP
+//	    port: "22"
+func EncodeFirewallRulesYAML(w io.Writer, rules []FirewallRule) error {
+	doc := firewallRulesYAML{
+		Rules: make([]firewallRuleYAML, 0, len(rules)),
+	}
+
+	for _, i := range firewallRulesOrder(rules) {
+		v := rules[i]
+		if v.NetworkInterfaceID < 1 {
+			return fmt.Errorf("rule %d: NetworkInterfaceID must be set", i)
+		}
+
+		if err := checkFirewallCommand(v.Command); err != nil {
+			return fmt.Errorf("rule %d: %s", i, err)
+		}
+
+		doc.Rules = append(doc.Rules, firewallRuleYAML{
+			Interface:     v.NetworkInterfaceID,
+			Command:       v.Command,
+			Protocol:      v.Protocol,
+			Address:       v.Address,
+			DestinationIP: v.DestinationIP,
+			Port:          v.Port,
+			SourcePort:    v.SourcePort,
+			Comment:       v.Comment,
+		})
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// DecodeFirewallRulesYAML read rules written by EncodeFirewallRulesYAML.
+// Unknown fields are reported as error. Position of the rules is set by
+// their order within the NetworkInterface.
+func DecodeFirewallRulesYAML(r io.Reader) ([]FirewallRule, error) {
+	var doc firewallRulesYAML
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	res := make([]FirewallRule, len(doc.Rules))
+	positions := make(map[int]int)
+
+	for i, v := range doc.Rules {
+		if v.Interface < 1 || v.Command == "" {
+			return nil, fmt.Errorf("rule %d: interface and command must be set", i)
+		}
+
+		positions[v.Interface]++
+		res[i] = FirewallRule{
+			NetworkInterfaceID: v.Interface,
+			Command:            v.Command,
+			Protocol:           v.Protocol,
+			Address:            v.Address,
+			DestinationIP:      v.DestinationIP,
+			Port:               v.Port,
+			SourcePort:         v.SourcePort,
+			Comment:            v.Comment,
+			Position:           positions[v.Interface],
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package onappgo
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var formatFirewallRules = []FirewallRule{
+	{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "22", Address: "10.0.0.0/8", Comment: `ssh "admins"`, Position: 1},
+	{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "80,443", SourcePort: "1024:65535", DestinationIP: "192.168.0.10", Position: 2},
+	{NetworkInterfaceID: 6, Command: "ACCEPT", Protocol: "ICMP", Port: "8", Position: 1},
+	{NetworkInterfaceID: 5, Command: "DROP", Protocol: "UDP", SourcePort: "53,123", Position: 3},
+	{NetworkInterfaceID: 6, Command: "DROP", Position: 2},
+}
+
+// sortedFirewallRules return rules in the order they are encoded
+func sortedFirewallRules(rules []FirewallRule) []FirewallRule {
+	res := make([]FirewallRule, 0, len(rules))
+	for _, i := range firewallRulesOrder(rules) {
+		res = append(res, rules[i])
+	}
+
+	return res
+}
+
+func TestFirewallRulesIptables_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeFirewallRulesIptables(&buf, formatFirewallRules))
+	require.Contains(t, buf.String(), `-A onapp-ni-5 -s 10.0.0.0/8 -p tcp -m tcp --dport 22 -m comment --comment "ssh \"admins\"" -j ACCEPT`)
+
+	got, err := DecodeFirewallRulesIptables(&buf)
+	require.NoError(t, err)
+	require.Equal(t, sortedFirewallRules(formatFirewallRules), got)
+}
+
+func TestFirewallRulesIptables_Unsupported(t *testing.T) {
+	inputs := map[string]string{
+		"table":    "*nat\nCOMMIT\n",
+		"chain":    "*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n",
+		"target":   "*filter\n-A onapp-ni-5 -p tcp -j REJECT\nCOMMIT\n",
+		"option":   "*filter\n-A onapp-ni-5 -i eth0 -j ACCEPT\nCOMMIT\n",
+		"match":    "*filter\n-A onapp-ni-5 -m state --state NEW -j ACCEPT\nCOMMIT\n",
+		"negation": "*filter\n-A onapp-ni-5 ! -s 10.0.0.1 -j ACCEPT\nCOMMIT\n",
+		"commit":   "*filter\n-A onapp-ni-5 -j ACCEPT\n",
+	}
+
+	for name, input := range inputs {
+		_, err := DecodeFirewallRulesIptables(strings.NewReader(input))
+		require.Error(t, err, name)
+	}
+
+	err := EncodeFirewallRulesIptables(&bytes.Buffer{}, []FirewallRule{{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "ICMP", SourcePort: "1"}})
+	require.Error(t, err)
+
+	err = EncodeFirewallRulesIptables(&bytes.Buffer{}, []FirewallRule{{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "80, 443"}})
+	require.Error(t, err)
+}
+
+func TestFirewallRulesYAML_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodeFirewallRulesYAML(&buf, formatFirewallRules))
+
+	got, err := DecodeFirewallRulesYAML(&buf)
+	require.NoError(t, err)
+	require.Equal(t, sortedFirewallRules(formatFirewallRules), got)
+
+	got, err = DecodeFirewallRulesYAML(strings.NewReader("rules:\n  - interface: 1\n    command: ACCEPT\n    port: 22\n"))
+	require.NoError(t, err)
+	require.Equal(t, "22", got[0].Port)
+
+	_, err = DecodeFirewallRulesYAML(strings.NewReader("rules:\n  - interface: 1\n    command: ACCEPT\n    state: NEW\n"))
+	require.Error(t, err)
+}
+
+func TestFirewallRules_RoundTripShuffled(t *testing.T) {
+	want := sortedFirewallRules(formatFirewallRules)
+	rnd := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 10; n++ {
+		shuffled := make([]FirewallRule, len(formatFirewallRules))
+		copy(shuffled, formatFirewallRules)
+		rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeFirewallRulesIptables(&buf, shuffled))
+		got, err := DecodeFirewallRulesIptables(&buf)
+		require.NoError(t, err)
+		require.Equal(t, want, got, "iptables %v", shuffled)
+
+		buf.Reset()
+		require.NoError(t, EncodeFirewallRulesYAML(&buf, shuffled))
+		got, err = DecodeFirewallRulesYAML(&buf)
+		require.NoError(t, err)
+		require.Equal(t, want, got, "yaml %v", shuffled)
+	}
+}
+
+func TestFirewallRules_EncodeUnsupportedCommand(t *testing.T) {
+	for _, command := range []string{"REJECT", "", "log"} {
+		rules := []FirewallRule{{NetworkInterfaceID: 5, Command: command, Protocol: "TCP"}}
+
+		require.Error(t, EncodeFirewallRulesIptables(&bytes.Buffer{}, rules), command)
+		require.Error(t, EncodeFirewallRulesYAML(&bytes.Buffer{}, rules), command)
+	}
+
+	require.NoError(t, EncodeFirewallRulesIptables(&bytes.Buffer{}, []FirewallRule{{NetworkInterfaceID: 5, Command: "drop"}}))
+}
@@ -0,0 +1,245 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// Firewall rule changes
+const (
+	FirewallRuleCreated = "create"
+	FirewallRuleUpdated = "update"
+	FirewallRuleDeleted = "delete"
+)
+
+// FirewallRuleSet - desired firewall rules of the VirtualMachine. Order of the
+// rules of the same NetworkInterface in the set defines their Position.
+type FirewallRuleSet []FirewallRule
+
+// FirewallRuleChange - single change made to converge firewall rules
+type FirewallRuleChange struct {
+	Action string
+
+	// Desired rule for create and update, current rule for delete
+	Rule FirewallRule
+
+	// Current rule for update
+	Previous *FirewallRule
+}
+
+// FirewallReconcileReport - changes made by the Reconcile
+type FirewallReconcileReport struct {
+	Changes []FirewallRuleChange
+
+	// Transaction of the firewall rules applying, nil if nothing changed
+	Transaction *Transaction
+}
+
+type firewallRuleKey struct {
+	networkInterfaceID int
+	command            string
+	protocol           string
+	address            string
+	port               string
+	sourcePort         string
+	destinationIP      string
+}
+
+func newFirewallRuleKey(rule *FirewallRule) firewallRuleKey {
+	return firewallRuleKey{
+		networkInterfaceID: rule.NetworkInterfaceID,
+		command:            strings.ToUpper(strings.TrimSpace(rule.Command)),
+		protocol:           strings.ToUpper(strings.TrimSpace(rule.Protocol)),
+		address:            strings.TrimSpace(rule.Address),
+		port:               strings.TrimSpace(rule.Port),
+		sourcePort:         strings.TrimSpace(rule.SourcePort),
+		destinationIP:      strings.TrimSpace(rule.DestinationIP),
+	}
+}
+
+// Changed check if any rule was changed
+func (r *FirewallReconcileReport) Changed() bool {
+	return len(r.Changes) > 0
+}
+
+// Count return number of changes of the action
+func (r *FirewallReconcileReport) Count(action string) int {
+	count := 0
+	for _, v := range r.Changes {
+		if v.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r *FirewallReconcileReport) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d deleted",
+		r.Count(FirewallRuleCreated), r.Count(FirewallRuleUpdated), r.Count(FirewallRuleDeleted))
+}
+
+// Validate check every rule of the set, returned error is *ValidationError
+// with fields of the rules prefixed by their index
+func (set FirewallRuleSet) Validate() error {
+	verr := &ValidationError{}
+
+	for i := range set {
+		err := firewallRuleRequest(&set[i]).Validate()
+		if rerr, ok := err.(*ValidationError); ok {
+			for field, messages := range rerr.Errors {
+				for _, m := range messages {
+					verr.Add(fmt.Sprintf("%d.%s", i, field), "%s", m)
+				}
+			}
+		}
+	}
+
+	return verr.errOrNil()
+}
+
+// positioned return copy of the set with Position set by the order of the
+// rules of every NetworkInterface
+func (set FirewallRuleSet) positioned() FirewallRuleSet {
+	res := make(FirewallRuleSet, len(set))
+	positions := make(map[int]int)
+
+	for i, v := range set {
+		positions[v.NetworkInterfaceID]++
+		v.Position = positions[v.NetworkInterfaceID]
+		res[i] = v
+	}
+
+	return res
+}
+
+// DiffFirewallRules return changes required to converge current rules to the
+// desired ones. Rules are matched by NetworkInterface, command, protocol,
+// address, port, source port and destination IP. Matched rules are updated if comment or position differs.
+func DiffFirewallRules(current []FirewallRule, desired FirewallRuleSet) []FirewallRuleChange {
+	unmatched := make(map[firewallRuleKey][]FirewallRule)
+	for _, v := range current {
+		key := newFirewallRuleKey(&v)
+		unmatched[key] = append(unmatched[key], v)
+	}
+
+	// keep the order of duplicates stable
+	for _, lst := range unmatched {
+		sort.SliceStable(lst, func(i, j int) bool { return lst[i].Position < lst[j].Position })
+	}
+
+	var creates, updates, deletes []FirewallRuleChange
+	for _, want := range desired.positioned() {
+		key := newFirewallRuleKey(&want)
+
+		lst := unmatched[key]
+		if len(lst) == 0 {
+			creates = append(creates, FirewallRuleChange{Action: FirewallRuleCreated, Rule: want})
+			continue
+		}
+
+		have := lst[0]
+		unmatched[key] = lst[1:]
+
+		if have.Comment != want.Comment || have.Position != want.Position {
+			want.ID = have.ID
+			prev := have
+			updates = append(updates, FirewallRuleChange{Action: FirewallRuleUpdated, Rule: want, Previous: &prev})
+		}
+	}
+
+	for _, v := range current {
+		key := newFirewallRuleKey(&v)
+		for _, rest := range unmatched[key] {
+			if rest.ID == v.ID {
+				deletes = append(deletes, FirewallRuleChange{Action: FirewallRuleDeleted, Rule: v})
+				break
+			}
+		}
+	}
+
+	// free positions before moving and creating rules
+	res := append(deletes, updates...)
+
+	return append(res, creates...)
+}
+
+// Reconcile create, update and delete firewall rules of the VirtualMachine to
+// converge them to the desired set, then apply firewall rules if anything
+// changed. Report contains changes made before the error, if any.
+func (s *FirewallRulesServiceOp) Reconcile(ctx context.Context, vmID int, desired FirewallRuleSet) (*FirewallReconcileReport, *Response, error) {
+	if vmID < 1 {
+		return nil, nil, godo.NewArgError("vmID", "cannot be less than 1")
+	}
+
+	if err := desired.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	current, resp, err := s.listAll(ctx, vmID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	report := &FirewallReconcileReport{}
+	for _, change := range DiffFirewallRules(current, desired) {
+		rule := change.Rule
+
+		switch change.Action {
+		case FirewallRuleDeleted:
+			resp, err = s.Delete(ctx, vmID, rule.ID, nil)
+		case FirewallRuleUpdated:
+			resp, err = s.Edit(ctx, vmID, rule.ID, firewallRuleRequest(&rule))
+		case FirewallRuleCreated:
+			var created *FirewallRule
+			created, resp, err = s.Create(ctx, vmID, firewallRuleRequest(&rule))
+			if created != nil {
+				change.Rule.ID = created.ID
+			}
+		}
+
+		if err != nil {
+			return report, resp, fmt.Errorf("%s of the firewall rule %s failed: %s", change.Action, godo.Stringify(rule), err)
+		}
+
+		report.Changes = append(report.Changes, change)
+	}
+
+	if !report.Changed() {
+		return report, resp, nil
+	}
+
+	report.Transaction, resp, err = s.client.VirtualMachineActions.ApplyFirewallRules(ctx, vmID)
+
+	return report, resp, err
+}
+
+// listAll return firewall rules of the VirtualMachine from all pages
+func (s *FirewallRulesServiceOp) listAll(ctx context.Context, vmID int) ([]FirewallRule, *Response, error) {
+	var res []FirewallRule
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, vmID, opt)
+		res = append(res, lst...)
+		return len(lst), resp, err
+	})
+
+	return res, resp, err
+}
+
+func firewallRuleRequest(rule *FirewallRule) *FirewallRuleCreateRequest {
+	return &FirewallRuleCreateRequest{
+		Address:            rule.Address,
+		Command:            strings.ToUpper(rule.Command),
+		Protocol:           strings.ToUpper(rule.Protocol),
+		NetworkInterfaceID: rule.NetworkInterfaceID,
+		Comment:            rule.Comment,
+		Port:               rule.Port,
+		Position:           rule.Position,
+		SourcePort:         rule.SourcePort,
+		DestinationIP:      rule.DestinationIP,
+	}
+}
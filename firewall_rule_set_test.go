@@ -0,0 +1,157 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFirewallRules(t *testing.T) {
+	current := []FirewallRule{
+		{ID: 1, NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "22", Position: 1},
+		{ID: 2, NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "80", Position: 2},
+		{ID: 3, NetworkInterfaceID: 5, Command: "DROP", Protocol: "UDP", Position: 3},
+	}
+
+	desired := FirewallRuleSet{
+		{NetworkInterfaceID: 5, Command: "accept", Protocol: "tcp", Port: "80"},
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "22"},
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "443", Comment: "https"},
+	}
+
+	changes := DiffFirewallRules(current, desired)
+	require.Len(t, changes, 4)
+
+	require.Equal(t, FirewallRuleDeleted, changes[0].Action)
+	require.Equal(t, 3, changes[0].Rule.ID)
+
+	require.Equal(t, FirewallRuleUpdated, changes[1].Action)
+	require.Equal(t, 2, changes[1].Rule.ID)
+	require.Equal(t, 1, changes[1].Rule.Position)
+
+	require.Equal(t, FirewallRuleUpdated, changes[2].Action)
+	require.Equal(t, 1, changes[2].Rule.ID)
+	require.Equal(t, 2, changes[2].Rule.Position)
+
+	require.Equal(t, FirewallRuleCreated, changes[3].Action)
+	require.Equal(t, 3, changes[3].Rule.Position)
+
+	require.Empty(t, DiffFirewallRules(current, FirewallRuleSet(current)))
+}
+
+func TestDiffFirewallRules_SourcePortAndDestinationIP(t *testing.T) {
+	current := []FirewallRule{
+		{ID: 1, NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "80", SourcePort: "1024:65535", Position: 1},
+		{ID: 2, NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "443", DestinationIP: "10.0.0.1", Position: 2},
+	}
+
+	desired := FirewallRuleSet{
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "80", SourcePort: "1024:65535"},
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "443", DestinationIP: "10.0.0.2"},
+	}
+
+	changes := DiffFirewallRules(current, desired)
+	require.Len(t, changes, 2)
+	require.Equal(t, FirewallRuleDeleted, changes[0].Action)
+	require.Equal(t, 2, changes[0].Rule.ID)
+	require.Equal(t, FirewallRuleCreated, changes[1].Action)
+
+	req := firewallRuleRequest(&changes[1].Rule)
+	require.Equal(t, "10.0.0.2", req.DestinationIP)
+	require.NoError(t, req.Validate())
+
+	require.Equal(t, "1024:65535", firewallRuleRequest(&current[0]).SourcePort)
+	require.Empty(t, DiffFirewallRules(current, FirewallRuleSet(current)))
+}
+
+func TestFirewallRules_Reconcile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `[{"firewall_rule":{"id":1,"network_interface_id":5,"command":"ACCEPT","protocol":"TCP","port":"22","position":1}},
+				{"firewall_rule":{"id":2,"network_interface_id":5,"command":"DROP","protocol":"UDP","position":2}}]`)
+		case http.MethodPost:
+			var got map[string]FirewallRuleCreateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			require.Equal(t, "443", got["firewall_rule"].Port)
+			require.Equal(t, 2, got["firewall_rule"].Position)
+			fmt.Fprint(w, `{"firewall_rule":{"id":3}}`)
+		}
+	})
+
+	deleted := false
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/2.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = true
+	})
+
+	applied := false
+	mux.HandleFunc("/virtual_machines/1/update_firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		applied = true
+	})
+
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"transaction":{"id":9,"action":"update_firewall_rules","associated_object_id":1,"associated_object_type":"VirtualMachine"}}]`)
+	})
+
+	desired := FirewallRuleSet{
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "22"},
+		{NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: "443"},
+	}
+
+	report, _, err := client.FirewallRules.Reconcile(ctx, 1, desired)
+	require.NoError(t, err)
+	require.Equal(t, "1 created, 0 updated, 1 deleted", report.String())
+	require.Equal(t, 3, report.Changes[1].Rule.ID)
+	require.True(t, deleted)
+	require.True(t, applied)
+	require.Equal(t, 9, report.Transaction.ID)
+}
+
+func TestFirewallRules_ReconcileAllPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// rules 101 - 150 are on the second page
+	var items []interface{}
+	var desired FirewallRuleSet
+	for i := 1; i <= 150; i++ {
+		rule := FirewallRule{ID: i, NetworkInterfaceID: 5, Command: "ACCEPT", Protocol: "TCP", Port: strconv.Itoa(1000 + i), Position: i}
+		items = append(items, rule)
+		if i != 150 {
+			desired = append(desired, rule)
+		}
+	}
+
+	mux.HandleFunc("/virtual_machines/1/firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "firewall_rule", items)
+	})
+
+	var deleted []string
+	mux.HandleFunc("/virtual_machines/1/firewall_rules/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = append(deleted, r.URL.Path)
+	})
+
+	mux.HandleFunc("/virtual_machines/1/update_firewall_rules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+	})
+
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"transaction":{"id":9,"action":"update_firewall_rules","associated_object_id":1,"associated_object_type":"VirtualMachine"}}]`)
+	})
+
+	report, _, err := client.FirewallRules.Reconcile(ctx, 1, desired)
+	require.NoError(t, err)
+	require.Equal(t, "0 created, 0 updated, 1 deleted", report.String())
+	require.Equal(t, []string{"/virtual_machines/1/firewall_rules/150.json"}, deleted)
+}
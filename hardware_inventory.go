@@ -0,0 +1,238 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/digitalocean/godo"
+)
+
+// Hardware device types of the inventory
+const (
+	HardwareDisk             = "disk"
+	HardwareDiskPci          = "disk_pci"
+	HardwareNetworkInterface = "network_interface"
+	HardwareCustom           = "custom"
+)
+
+// HardwareInventoryOptions -
+type HardwareInventoryOptions struct {
+	// Number of Hypervisors queried at the same time, 1 if zero
+	Concurrency int
+
+	// Rescan devices on the Hypervisors instead of listing known ones
+	Refresh bool
+}
+
+// HardwareInventoryRow - single hardware device of the Hypervisor
+type HardwareInventoryRow struct {
+	HypervisorID    int    `json:"hypervisor_id"`
+	HypervisorLabel string `json:"hypervisor_label"`
+	DeviceType      string `json:"device_type"`
+	DeviceID        int    `json:"device_id"`
+	Name            string `json:"name,omitempty"`
+	Pci             string `json:"pci,omitempty"`
+	Scsi            string `json:"scsi,omitempty"`
+	Mac             string `json:"mac,omitempty"`
+	InterfaceType   string `json:"interface_type,omitempty"`
+	Code            string `json:"code,omitempty"`
+	Status          string `json:"status,omitempty"`
+}
+
+// HardwareInventory - hardware devices of all Hypervisors. Hypervisors which
+// devices were not collected are listed in Errors.
+type HardwareInventory struct {
+	Rows   []HardwareInventoryRow
+	Errors map[int]error
+}
+
+var hardwareInventoryColumns = []string{
+	"hypervisor_id", "hypervisor_label", "device_type", "device_id",
+	"name", "pci", "scsi", "mac", "interface_type", "code", "status",
+}
+
+// HardwareInventoryRows return rows of all devices of the Hypervisor
+func HardwareInventoryRows(hv *Hypervisor, devices *HardwareDevices) []HardwareInventoryRow {
+	var res []HardwareInventoryRow
+	row := func(deviceType string, id int) HardwareInventoryRow {
+		return HardwareInventoryRow{
+			HypervisorID:    hv.ID,
+			HypervisorLabel: hv.Label,
+			DeviceType:      deviceType,
+			DeviceID:        id,
+		}
+	}
+
+	for _, v := range devices.HardwareDiskDevice {
+		r := row(HardwareDisk, v.ID)
+		r.Name, r.Scsi, r.Status = v.Name, v.Scsi, v.Status
+		res = append(res, r)
+	}
+
+	for _, v := range devices.HardwareDiskPciDevice {
+		r := row(HardwareDiskPci, v.ID)
+		r.Pci, r.Status = v.Pci, v.Status
+		res = append(res, r)
+	}
+
+	for _, v := range devices.HardwareNetworkInterfaceDevice {
+		r := row(HardwareNetworkInterface, v.ID)
+		r.Name, r.Pci, r.Mac, r.InterfaceType, r.Status = v.Name, v.Pci, v.Mac, v.InterfaceType, v.Status
+		res = append(res, r)
+	}
+
+	for _, v := range devices.HardwareCustomDevice {
+		r := row(HardwareCustom, v.ID)
+		r.Name, r.Pci, r.Code, r.Status = v.Name, v.Pci, v.Code, v.Status
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// WriteCSV write inventory as CSV with header
+func (inv *HardwareInventory) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(hardwareInventoryColumns); err != nil {
+		return err
+	}
+
+	for _, v := range inv.Rows {
+		record := []string{
+			strconv.Itoa(v.HypervisorID), v.HypervisorLabel, v.DeviceType, strconv.Itoa(v.DeviceID),
+			v.Name, v.Pci, v.Scsi, v.Mac, v.InterfaceType, v.Code, v.Status,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteJSON write rows of the inventory as JSON array
+func (inv *HardwareInventory) WriteJSON(w io.Writer) error {
+	rows := inv.Rows
+	if rows == nil {
+		rows = []HardwareInventoryRow{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(rows)
+}
+
+// HardwareDevices - List known hardware devices of the Hypervisor without rescan
+func (s *HypervisorsServiceOp) HardwareDevices(ctx context.Context, id int) (*HardwareDevices, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(hypervisorHardwareDeviceBasePath, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &rootHardware{}
+	resp, err := s.client.Do(ctx, req, out)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res := &HardwareDevices{}
+	res.initHardwareDevices(out)
+
+	return res, resp, err
+}
+
+// Inventory collect hardware devices of all Hypervisors concurrently. Error
+// is returned only if Hypervisors cannot be listed, errors of the single
+// Hypervisors are kept in the inventory.
+func (s *HypervisorsServiceOp) Inventory(ctx context.Context, opts *HardwareInventoryOptions) (*HardwareInventory, *Response, error) {
+	if opts == nil {
+		opts = &HardwareInventoryOptions{}
+	}
+
+	var hvs []Hypervisor
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, opt)
+		hvs = append(hvs, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	inv := &HardwareInventory{
+		Errors: make(map[int]error),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i := range hvs {
+		wg.Add(1)
+		go func(hv *Hypervisor) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				mu.Lock()
+				inv.Errors[hv.ID] = ctx.Err()
+				mu.Unlock()
+				return
+			}
+			defer func() { <-sem }()
+
+			var devices *HardwareDevices
+			var err error
+			if opts.Refresh {
+				devices, _, err = s.Refresh(ctx, hv.ID)
+			} else {
+				devices, _, err = s.HardwareDevices(ctx, hv.ID)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				inv.Errors[hv.ID] = err
+				return
+			}
+			inv.Rows = append(inv.Rows, HardwareInventoryRows(hv, devices)...)
+		}(&hvs[i])
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(inv.Rows, func(i, j int) bool {
+		a, b := inv.Rows[i], inv.Rows[j]
+		if a.HypervisorID != b.HypervisorID {
+			return a.HypervisorID < b.HypervisorID
+		}
+		if a.DeviceType != b.DeviceType {
+			return a.DeviceType < b.DeviceType
+		}
+		return a.DeviceID < b.DeviceID
+	})
+
+	return inv, resp, nil
+}
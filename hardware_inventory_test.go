@@ -0,0 +1,70 @@
+package onappgo
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHypervisors_Inventory(t *testing.T) {
+	setup()
+	defer teardown()
+
+	hvs := []interface{}{Hypervisor{ID: 2, Label: "hv-2"}, Hypervisor{ID: 1, Label: "hv-1"}, Hypervisor{ID: 3, Label: "hv-3"}}
+	for i := 4; i <= 150; i++ {
+		hvs = append(hvs, Hypervisor{ID: i, Label: fmt.Sprintf("hv-%d", i)})
+	}
+
+	mux.HandleFunc("/settings/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		require.NotEmpty(t, r.URL.Query().Get("per_page"))
+		writeTestPage(t, w, r, "hypervisor", hvs)
+	})
+
+	// only the Hypervisor from the second page has devices among the rest
+	mux.HandleFunc("/settings/hypervisors/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/settings/hypervisors/150/hardware_devices.json" {
+			fmt.Fprint(w, `[{"hardware_network_interface_device":{"id":9,"name":"eth1","mac":"00:11:22:33:44:66"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	mux.HandleFunc("/settings/hypervisors/1/hardware_devices.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[{"hardware_network_interface_device":{"id":5,"name":"eth0","mac":"00:11:22:33:44:55","status":"unassigned"}},
+			{"hardware_disk_device":{"id":4,"name":"sda","scsi":"0:0:0:0","status":"assigned_to_storage"}}]`)
+	})
+
+	mux.HandleFunc("/settings/hypervisors/2/hardware_devices.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"hardware_disk_pci_device":{"id":7,"pci":"0000:00:1f.2"}}]`)
+	})
+
+	mux.HandleFunc("/settings/hypervisors/3/hardware_devices.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors":["not found"]}`, http.StatusNotFound)
+	})
+
+	inv, _, err := client.Hypervisors.Inventory(ctx, &HardwareInventoryOptions{Concurrency: 2})
+	require.NoError(t, err)
+	require.Len(t, inv.Rows, 4)
+	require.Len(t, inv.Errors, 1)
+	require.Error(t, inv.Errors[3])
+
+	var buf bytes.Buffer
+	require.NoError(t, inv.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"hypervisor_id,hypervisor_label,device_type,device_id,name,pci,scsi,mac,interface_type,code,status",
+		"1,hv-1,disk,4,sda,,0:0:0:0,,,,assigned_to_storage",
+		"1,hv-1,network_interface,5,eth0,,,00:11:22:33:44:55,,,unassigned",
+		"2,hv-2,disk_pci,7,,0000:00:1f.2,,,,,",
+		"150,hv-150,network_interface,9,eth1,,,00:11:22:33:44:66,,,",
+	}, lines)
+
+	buf.Reset()
+	require.NoError(t, inv.WriteJSON(&buf))
+	require.Contains(t, buf.String(), `"device_type": "disk_pci"`)
+}
@@ -0,0 +1,329 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/digitalocean/godo"
+)
+
+const hypervisorMaintenanceModeBasePath string = hypervisorsBasePath + "/%d/maintenance_mode"
+
+// HypervisorDrainOptions -
+type HypervisorDrainOptions struct {
+	// Only plan migrations, maintenance mode is not changed
+	DryRun bool
+
+	// Number of VirtualMachines migrated at the same time, 1 if zero
+	Concurrency int
+
+	// Use only these Hypervisors as destinations, all Hypervisors of the
+	// same HypervisorGroup if empty
+	DestinationIDs []int
+
+	// Options of waiting for migration transactions
+	Wait *TransactionWaitOptions
+}
+
+// HypervisorMigration - migration of the VirtualMachine to the destination
+// Hypervisor, DestinationID is 0 if no destination was found
+type HypervisorMigration struct {
+	VirtualMachine VirtualMachine
+	DestinationID  int
+	Transaction    *Transaction
+	Err            error
+}
+
+// HypervisorDrainPlan - migrations required to drain the Hypervisor
+type HypervisorDrainPlan struct {
+	Migrations []HypervisorMigration
+
+	// VirtualMachines without suitable destination
+	Unplaced []HypervisorMigration
+}
+
+// HypervisorDrainReport represent result of the Drain
+type HypervisorDrainReport struct {
+	HypervisorID int
+	DryRun       bool
+	Migrations   []HypervisorMigration
+
+	// VirtualMachines still placed on the Hypervisor after drain
+	Stragglers []VirtualMachine
+}
+
+// Failed return migrations finished with error
+func (r *HypervisorDrainReport) Failed() []HypervisorMigration {
+	var res []HypervisorMigration
+	for _, v := range r.Migrations {
+		if v.Err != nil {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Drained check if no VirtualMachines left on the Hypervisor
+func (r *HypervisorDrainReport) Drained() bool {
+	return !r.DryRun && len(r.Stragglers) == 0
+}
+
+// String return one line per migration, e.g. dry-run output
+func (r *HypervisorDrainReport) String() string {
+	var b strings.Builder
+
+	mode := ""
+	if r.DryRun {
+		mode = " (dry-run)"
+	}
+	fmt.Fprintf(&b, "Hypervisor [%d] drain%s\n", r.HypervisorID, mode)
+
+	for _, v := range r.Migrations {
+		vm := v.VirtualMachine
+		switch {
+		case v.DestinationID == 0:
+			fmt.Fprintf(&b, "  VirtualMachine [%d] %s (%d MB): no destination: %s\n", vm.ID, vm.Label, vm.Memory, v.Err)
+		case v.Err != nil:
+			fmt.Fprintf(&b, "  VirtualMachine [%d] %s (%d MB) -> Hypervisor [%d]: %s\n", vm.ID, vm.Label, vm.Memory, v.DestinationID, v.Err)
+		default:
+			fmt.Fprintf(&b, "  VirtualMachine [%d] %s (%d MB) -> Hypervisor [%d]\n", vm.ID, vm.Label, vm.Memory, v.DestinationID)
+		}
+	}
+
+	for _, vm := range r.Stragglers {
+		fmt.Fprintf(&b, "  straggler VirtualMachine [%d] %s\n", vm.ID, vm.Label)
+	}
+
+	return b.String()
+}
+
+// PlanHypervisorDrain choose destination for every VirtualMachine of the
+// source Hypervisor among candidates. Candidate must be online, enabled, not
+// in maintenance mode, belong to the same HypervisorGroup and support all CPU
+// flags of the source. Largest VirtualMachines are placed first, every one to
+// the candidate with most free memory left.
+func PlanHypervisorDrain(source *Hypervisor, candidates []Hypervisor, vms []VirtualMachine) *HypervisorDrainPlan {
+	type destination struct {
+		id      int
+		freeMem int
+	}
+
+	var dests []*destination
+	for _, hv := range candidates {
+		if hypervisorDrainDestination(source, &hv) {
+			dests = append(dests, &destination{id: hv.ID, freeMem: hv.FreeMemory})
+		}
+	}
+
+	sorted := make([]VirtualMachine, len(vms))
+	copy(sorted, vms)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Memory > sorted[j].Memory })
+
+	plan := &HypervisorDrainPlan{}
+	for _, vm := range sorted {
+		var best *destination
+		for _, d := range dests {
+			if d.freeMem >= vm.Memory && (best == nil || d.freeMem > best.freeMem) {
+				best = d
+			}
+		}
+
+		if best == nil {
+			plan.Unplaced = append(plan.Unplaced, HypervisorMigration{
+				VirtualMachine: vm,
+				Err:            fmt.Errorf("not enough free memory on Hypervisors of the HypervisorGroup [%d]", source.HypervisorGroupID),
+			})
+			continue
+		}
+
+		best.freeMem -= vm.Memory
+		plan.Migrations = append(plan.Migrations, HypervisorMigration{
+			VirtualMachine: vm,
+			DestinationID:  best.id,
+		})
+	}
+
+	return plan
+}
+
+func hypervisorDrainDestination(source *Hypervisor, hv *Hypervisor) bool {
+	if hv.ID == source.ID || hv.HypervisorGroupID != source.HypervisorGroupID {
+		return false
+	}
+
+	if !hv.Online || !hv.Enabled || hv.MaintenanceMode {
+		return false
+	}
+
+	flags := make(map[string]bool, len(hv.CPUFlags))
+	for _, v := range hv.CPUFlags {
+		flags[v] = true
+	}
+
+	for _, v := range source.CPUFlags {
+		if !flags[v] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EnableMaintenanceMode put Hypervisor to the maintenance mode, no new
+// VirtualMachines are placed to it
+func (s *HypervisorsServiceOp) EnableMaintenanceMode(ctx context.Context, id int) (*Response, error) {
+	return s.maintenanceMode(ctx, id, http.MethodPut, "EnableMaintenanceMode")
+}
+
+// DisableMaintenanceMode return Hypervisor from the maintenance mode
+func (s *HypervisorsServiceOp) DisableMaintenanceMode(ctx context.Context, id int) (*Response, error) {
+	return s.maintenanceMode(ctx, id, http.MethodDelete, "DisableMaintenanceMode")
+}
+
+func (s *HypervisorsServiceOp) maintenanceMode(ctx context.Context, id int, method string, name string) (*Response, error) {
+	if id < 1 {
+		return nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(hypervisorMaintenanceModeBasePath, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Hypervisor [%s] req: %v\n", name, req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Drain put Hypervisor to the maintenance mode and migrate all its
+// VirtualMachines to other Hypervisors of the same HypervisorGroup. Report
+// contains VirtualMachines left on the Hypervisor, error is returned if any
+// of them left. With DryRun only planned migrations are reported.
+func (s *HypervisorsServiceOp) Drain(ctx context.Context, id int, opts *HypervisorDrainOptions) (*HypervisorDrainReport, *Response, error) {
+	if opts == nil {
+		opts = &HypervisorDrainOptions{}
+	}
+
+	source, resp, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	candidates, resp, err := s.drainCandidates(ctx, opts.DestinationIDs)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	vms, resp, err := s.client.VirtualMachines.Select(ctx, &VirtualMachineSelector{HypervisorID: id})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	plan := PlanHypervisorDrain(source, candidates, vms)
+	report := &HypervisorDrainReport{
+		HypervisorID: id,
+		DryRun:       opts.DryRun,
+		Migrations:   append(plan.Migrations, plan.Unplaced...),
+	}
+
+	if opts.DryRun {
+		return report, resp, nil
+	}
+
+	if !source.MaintenanceMode {
+		resp, err = s.EnableMaintenanceMode(ctx, id)
+		if err != nil {
+			return report, resp, err
+		}
+	}
+
+	s.migrate(ctx, report.Migrations[:len(plan.Migrations)], opts)
+
+	report.Stragglers, resp, err = s.client.VirtualMachines.Select(ctx, &VirtualMachineSelector{HypervisorID: id})
+	if err != nil {
+		return report, resp, err
+	}
+
+	if len(report.Stragglers) > 0 {
+		return report, resp, fmt.Errorf("Hypervisor [%d] is not drained, %d VirtualMachines left", id, len(report.Stragglers))
+	}
+
+	return report, resp, nil
+}
+
+// Undrain return drained Hypervisor from the maintenance mode, migrated
+// VirtualMachines are not moved back
+func (s *HypervisorsServiceOp) Undrain(ctx context.Context, id int) (*Response, error) {
+	return s.DisableMaintenanceMode(ctx, id)
+}
+
+func (s *HypervisorsServiceOp) drainCandidates(ctx context.Context, ids []int) ([]Hypervisor, *Response, error) {
+	if len(ids) == 0 {
+		var res []Hypervisor
+		resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.List(ctx, opt)
+			res = append(res, lst...)
+			return len(lst), resp, err
+		})
+
+		return res, resp, err
+	}
+
+	var res []Hypervisor
+	var resp *Response
+	for _, id := range ids {
+		hv, r, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, r, err
+		}
+
+		res = append(res, *hv)
+		resp = r
+	}
+
+	return res, resp, nil
+}
+
+func (s *HypervisorsServiceOp) migrate(ctx context.Context, migrations []HypervisorMigration, opts *HypervisorDrainOptions) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i := range migrations {
+		wg.Add(1)
+		go func(m *HypervisorMigration) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				m.Err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			m.Transaction, _, m.Err = s.client.VirtualMachineActions.Migrate(ctx, m.VirtualMachine.ID, m.DestinationID)
+			if m.Err != nil || m.Transaction == nil {
+				return
+			}
+
+			var lst []Transaction
+			lst, _, m.Err = s.client.Transactions.Wait(ctx, []Transaction{*m.Transaction}, opts.Wait)
+			if len(lst) > 0 {
+				m.Transaction = &lst[0]
+			}
+		}(&migrations[i])
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,127 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanHypervisorDrain(t *testing.T) {
+	source := &Hypervisor{ID: 1, HypervisorGroupID: 10, CPUFlags: []string{"sse4_2", "avx"}}
+	candidates := []Hypervisor{
+		*source,
+		{ID: 2, HypervisorGroupID: 10, Online: true, Enabled: true, FreeMemory: 4096, CPUFlags: []string{"sse4_2", "avx", "avx2"}},
+		{ID: 3, HypervisorGroupID: 10, Online: true, Enabled: true, FreeMemory: 3072, CPUFlags: []string{"sse4_2", "avx"}},
+		{ID: 4, HypervisorGroupID: 10, Online: true, Enabled: true, FreeMemory: 65536, CPUFlags: []string{"sse4_2"}},
+		{ID: 5, HypervisorGroupID: 10, Online: true, Enabled: true, FreeMemory: 65536, MaintenanceMode: true, CPUFlags: source.CPUFlags},
+		{ID: 6, HypervisorGroupID: 11, Online: true, Enabled: true, FreeMemory: 65536, CPUFlags: source.CPUFlags},
+	}
+	vms := []VirtualMachine{
+		{ID: 101, Memory: 1024},
+		{ID: 102, Memory: 3072},
+		{ID: 103, Memory: 2048},
+		{ID: 104, Memory: 8192},
+	}
+
+	plan := PlanHypervisorDrain(source, candidates, vms)
+
+	placed := make(map[int]int)
+	for _, m := range plan.Migrations {
+		placed[m.VirtualMachine.ID] = m.DestinationID
+	}
+	require.Equal(t, map[int]int{102: 2, 103: 3, 101: 2}, placed)
+
+	require.Len(t, plan.Unplaced, 1)
+	require.Equal(t, 104, plan.Unplaced[0].VirtualMachine.ID)
+	require.Error(t, plan.Unplaced[0].Err)
+}
+
+func TestHypervisors_Drain(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/hypervisors/1.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"hypervisor":{"id":1,"hypervisor_group_id":10,"online":true,"enabled":true}}`)
+	})
+
+	// the only Hypervisor with free memory is on the second page
+	hypervisors := []interface{}{Hypervisor{ID: 1, HypervisorGroupID: 10, Online: true, Enabled: true}}
+	for i := 3; i <= 101; i++ {
+		hypervisors = append(hypervisors, Hypervisor{ID: i, HypervisorGroupID: 11, Online: true, Enabled: true, FreeMemory: 4096})
+	}
+	hypervisors = append(hypervisors, Hypervisor{ID: 2, HypervisorGroupID: 10, Online: true, Enabled: true, FreeMemory: 4096})
+
+	mux.HandleFunc("/settings/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		require.NotEmpty(t, r.URL.Query().Get("per_page"))
+		writeTestPage(t, w, r, "hypervisor", hypervisors)
+	})
+
+	var mu sync.Mutex
+	migrated := make(map[int]int)
+	mux.HandleFunc("/settings/hypervisors/1/virtual_machines.json", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprint(w, "[")
+		sep := ""
+		for _, id := range []int{7, 8} {
+			if _, ok := migrated[id]; !ok {
+				fmt.Fprintf(w, `%s{"virtual_machine":{"id":%d,"hypervisor_id":1,"memory":1024,"booted":true}}`, sep, id)
+				sep = ","
+			}
+		}
+		fmt.Fprint(w, "]")
+	})
+
+	maintenance := false
+	mux.HandleFunc("/settings/hypervisors/1/maintenance_mode.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		maintenance = true
+	})
+
+	for _, id := range []int{7, 8} {
+		id := id
+		mux.HandleFunc(fmt.Sprintf("/virtual_machines/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"virtual_machine":{"id":%d,"hypervisor_id":1,"booted":true}}`, id)
+		})
+
+		mux.HandleFunc(fmt.Sprintf("/virtual_machines/%d/migration.json", id), func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+
+			var got virtualMachineMigrationRequestRoot
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+			mu.Lock()
+			migrated[id] = got.VirtualMachine.Destination
+			mu.Unlock()
+		})
+	}
+
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"transaction":{"id":21,"action":"hot_migrate","associated_object_id":7,"associated_object_type":"VirtualMachine","status":"complete"}},
+			{"transaction":{"id":22,"action":"hot_migrate","associated_object_id":8,"associated_object_type":"VirtualMachine","status":"complete"}}]`)
+	})
+
+	report, _, err := client.Hypervisors.Drain(ctx, 1, &HypervisorDrainOptions{DryRun: true})
+	require.NoError(t, err)
+	require.False(t, maintenance)
+	require.Len(t, report.Migrations, 2)
+	require.Empty(t, migrated)
+	require.Contains(t, report.String(), "(dry-run)")
+
+	opts := &HypervisorDrainOptions{
+		Concurrency: 2,
+		Wait:        &TransactionWaitOptions{Interval: time.Millisecond},
+	}
+	report, _, err = client.Hypervisors.Drain(ctx, 1, opts)
+	require.NoError(t, err)
+	require.True(t, maintenance)
+	require.True(t, report.Drained())
+	require.Empty(t, report.Failed())
+	require.Equal(t, map[int]int{7: 2, 8: 2}, migrated)
+}
@@ -27,6 +27,8 @@ type HypervisorGroupsService interface {
 	// TODO !!!
 	// Move next functions to the HypervisorGroupActionsService
 	ListOfAttachedComputeResources(context.Context, int) ([]Hypervisor, *Response, error)
+
+	PlanCapacity(context.Context, int, int, int, *CapacityPlanOptions) (*CapacityPlan, *Response, error)
 }
 
 // HypervisorGroupsServiceOp handles communication with the Compute Zone
@@ -0,0 +1,335 @@
+package onappgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hypervisor health events
+const (
+	HypervisorEventOffline          = "offline"
+	HypervisorEventStale            = "stale"
+	HypervisorEventZombieDomains    = "zombie_domains"
+	HypervisorEventFailoverDisabled = "failover_disabled"
+)
+
+const (
+	defaultHypervisorMonitorInterval   = time.Minute
+	defaultHypervisorMonitorStaleAfter = 5 * time.Minute
+)
+
+// HypervisorEvent - health condition of the Hypervisor is raised or resolved.
+// Event is emitted only when condition changes, so the same condition is not
+// reported on every poll.
+type HypervisorEvent struct {
+	Type         string    `json:"type"`
+	Resolved     bool      `json:"resolved"`
+	HypervisorID int       `json:"hypervisor_id"`
+	Label        string    `json:"label"`
+	Message      string    `json:"message"`
+	Time         time.Time `json:"time"`
+}
+
+// HypervisorEventSink receive events of the HypervisorMonitor
+type HypervisorEventSink interface {
+	Send(context.Context, *HypervisorEvent) error
+}
+
+// HypervisorEventFunc - callback used as HypervisorEventSink
+type HypervisorEventFunc func(context.Context, *HypervisorEvent) error
+
+// Send call the callback
+func (f HypervisorEventFunc) Send(ctx context.Context, event *HypervisorEvent) error {
+	return f(ctx, event)
+}
+
+// HypervisorLogSink write events to the Logger, standard logger if nil
+type HypervisorLogSink struct {
+	Logger *log.Logger
+}
+
+// Send write event to the log
+func (s *HypervisorLogSink) Send(ctx context.Context, event *HypervisorEvent) error {
+	logf := log.Printf
+	if s.Logger != nil {
+		logf = s.Logger.Printf
+	}
+
+	logf("Hypervisor [%d] %s: %s\n", event.HypervisorID, event.Label, event.Message)
+
+	return nil
+}
+
+// HypervisorWebhookSink POST events as JSON to the URL
+type HypervisorWebhookSink struct {
+	URL     string
+	Headers map[string]string
+
+	// http.DefaultClient if nil
+	Client *http.Client
+}
+
+// Send POST event to the webhook, any status except 2xx is an error
+func (s *HypervisorWebhookSink) Send(ctx context.Context, event *HypervisorEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", mediaType)
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s returned %s", s.URL, resp.Status)
+	}
+
+	return nil
+}
+
+// HypervisorMonitorOptions -
+type HypervisorMonitorOptions struct {
+	// Polling interval, 1 minute if zero
+	Interval time.Duration
+
+	// Hypervisor is stale if it has not called in longer, 5 minutes if zero
+	StaleAfter time.Duration
+
+	Sinks []HypervisorEventSink
+
+	// Called on errors of polling and sinks, errors are logged if nil
+	OnError func(error)
+}
+
+// HypervisorMonitor poll Hypervisors and emit events on changes of their
+// health conditions
+type HypervisorMonitor struct {
+	client *Client
+	opts   HypervisorMonitorOptions
+
+	mu sync.Mutex
+
+	// active conditions by Hypervisor ID
+	state map[int]map[string]bool
+}
+
+// NewHypervisorMonitor returns a new HypervisorMonitor
+func NewHypervisorMonitor(client *Client, opts *HypervisorMonitorOptions) *HypervisorMonitor {
+	m := &HypervisorMonitor{
+		client: client,
+		state:  make(map[int]map[string]bool),
+	}
+
+	if opts != nil {
+		m.opts = *opts
+	}
+
+	if m.opts.Interval <= 0 {
+		m.opts.Interval = defaultHypervisorMonitorInterval
+	}
+
+	if m.opts.StaleAfter <= 0 {
+		m.opts.StaleAfter = defaultHypervisorMonitorStaleAfter
+	}
+
+	return m
+}
+
+// Run poll Hypervisors on the interval until the context is done
+func (m *HypervisorMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Poll(ctx); err != nil {
+			m.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll Hypervisors once and send events to the sinks. Event not delivered
+// to any of the sinks is emitted again on the next poll, so sinks which
+// received it already may get it twice.
+func (m *HypervisorMonitor) Poll(ctx context.Context) ([]HypervisorEvent, error) {
+	var hvs []Hypervisor
+	_, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := m.client.Hypervisors.List(ctx, opt)
+		hvs = append(hvs, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := m.Evaluate(hvs, time.Now())
+
+	failed := 0
+	for i := range events {
+		delivered := true
+		for _, sink := range m.opts.Sinks {
+			if err := sink.Send(ctx, &events[i]); err != nil {
+				delivered = false
+				m.onError(fmt.Errorf("Hypervisor [%d] %s event: %s", events[i].HypervisorID, events[i].Type, err))
+			}
+		}
+
+		if !delivered {
+			failed++
+			m.rollback(&events[i])
+		}
+	}
+
+	if failed > 0 {
+		return events, fmt.Errorf("%d events were not delivered", failed)
+	}
+
+	return events, nil
+}
+
+// Evaluate health conditions of the Hypervisors at the time and return events
+// for conditions raised or resolved since the previous evaluation.
+// Hypervisors missing in the list are forgotten.
+func (m *HypervisorMonitor) Evaluate(hvs []Hypervisor, now time.Time) []HypervisorEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []HypervisorEvent
+	seen := make(map[int]bool, len(hvs))
+
+	for i := range hvs {
+		hv := &hvs[i]
+		seen[hv.ID] = true
+
+		prev := m.state[hv.ID]
+		cur := m.conditions(hv, now)
+
+		for _, condition := range hypervisorConditions {
+			message, active := cur[condition]
+			if active == prev[condition] {
+				continue
+			}
+
+			event := HypervisorEvent{
+				Type:         condition,
+				Resolved:     !active,
+				HypervisorID: hv.ID,
+				Label:        hv.Label,
+				Message:      message,
+				Time:         now,
+			}
+			if !active {
+				event.Message = condition + " resolved"
+			}
+
+			events = append(events, event)
+		}
+
+		state := make(map[string]bool, len(cur))
+		for k := range cur {
+			state[k] = true
+		}
+		m.state[hv.ID] = state
+	}
+
+	for id := range m.state {
+		if !seen[id] {
+			delete(m.state, id)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].HypervisorID < events[j].HypervisorID })
+
+	return events
+}
+
+// rollback restore the condition of the event to its state before the
+// evaluation, so the event is emitted again
+func (m *HypervisorMonitor) rollback(event *HypervisorEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.state[event.HypervisorID]
+	if !ok {
+		return
+	}
+
+	if event.Resolved {
+		state[event.Type] = true
+	} else {
+		delete(state, event.Type)
+	}
+}
+
+var hypervisorConditions = []string{
+	HypervisorEventOffline,
+	HypervisorEventStale,
+	HypervisorEventZombieDomains,
+	HypervisorEventFailoverDisabled,
+}
+
+// conditions return active conditions of the Hypervisor with their messages
+func (m *HypervisorMonitor) conditions(hv *Hypervisor, now time.Time) map[string]string {
+	res := make(map[string]string)
+
+	// going offline is expected while rebooting
+	if !hv.Online && !hv.Rebooting {
+		res[HypervisorEventOffline] = fmt.Sprintf("offline, failure count %d", hv.FailureCount)
+	}
+
+	if t, err := time.Parse(time.RFC3339, hv.CalledInAt); err == nil {
+		if age := now.Sub(t); age > m.opts.StaleAfter {
+			res[HypervisorEventStale] = fmt.Sprintf("last called in %s ago", age.Round(time.Second))
+		}
+	}
+
+	zombies := strings.Trim(hv.ListOfZombieDomains, "[] \n")
+	if zombies != "" || hv.TotalZombieMem > 0 {
+		res[HypervisorEventZombieDomains] = fmt.Sprintf("zombie domains [%s] use %d MB", zombies, hv.TotalZombieMem)
+	}
+
+	if hv.DisableFailover {
+		res[HypervisorEventFailoverDisabled] = "failover is disabled"
+	}
+
+	return res
+}
+
+func (m *HypervisorMonitor) onError(err error) {
+	if m.opts.OnError != nil {
+		m.opts.OnError(err)
+		return
+	}
+
+	log.Println("HypervisorMonitor error: ", err)
+}
@@ -0,0 +1,167 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHypervisorMonitor_Evaluate(t *testing.T) {
+	now := time.Date(2020, 4, 14, 12, 0, 0, 0, time.UTC)
+	m := NewHypervisorMonitor(nil, &HypervisorMonitorOptions{StaleAfter: 10 * time.Minute})
+
+	hvs := []Hypervisor{
+		{ID: 1, Online: false, CalledInAt: "2020-04-14T11:00:00Z"},
+		{ID: 2, Online: false, Rebooting: true, ListOfZombieDomains: "[]"},
+		{ID: 3, Online: true, ListOfZombieDomains: "[vm-1]", TotalZombieMem: 512, DisableFailover: true},
+	}
+
+	events := m.Evaluate(hvs, now)
+	types := make(map[int][]string)
+	for _, e := range events {
+		require.False(t, e.Resolved)
+		types[e.HypervisorID] = append(types[e.HypervisorID], e.Type)
+	}
+	require.Equal(t, map[int][]string{
+		1: {HypervisorEventOffline, HypervisorEventStale},
+		3: {HypervisorEventZombieDomains, HypervisorEventFailoverDisabled},
+	}, types)
+
+	// nothing changed, no alert storm
+	require.Empty(t, m.Evaluate(hvs, now.Add(time.Minute)))
+
+	hvs[0].Online = true
+	hvs[0].CalledInAt = "2020-04-14T12:01:00Z"
+	events = m.Evaluate(hvs[:1], now.Add(2*time.Minute))
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.True(t, e.Resolved)
+		require.Equal(t, 1, e.HypervisorID)
+	}
+
+	// forgotten Hypervisor is reported again
+	events = m.Evaluate(hvs, now.Add(3*time.Minute))
+	require.Len(t, events, 2)
+	require.Equal(t, 3, events[0].HypervisorID)
+}
+
+func TestHypervisorMonitor_Poll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"hypervisor":{"id":1,"label":"hv-1","online":false}}]`)
+	})
+
+	var hooked []HypervisorEvent
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		require.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		var event HypervisorEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		hooked = append(hooked, event)
+	})
+
+	var called []HypervisorEvent
+	m := NewHypervisorMonitor(client, &HypervisorMonitorOptions{
+		Sinks: []HypervisorEventSink{
+			HypervisorEventFunc(func(ctx context.Context, e *HypervisorEvent) error {
+				called = append(called, *e)
+				return nil
+			}),
+			&HypervisorWebhookSink{URL: server.URL + "/hook", Headers: map[string]string{"X-Token": "secret"}},
+			&HypervisorLogSink{},
+		},
+	})
+
+	events, err := m.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, HypervisorEventOffline, events[0].Type)
+	require.Equal(t, "hv-1", called[0].Label)
+	require.Len(t, hooked, 1)
+	require.Equal(t, 1, hooked[0].HypervisorID)
+
+	events, err = m.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestHypervisorMonitor_PollAllPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var hvs []interface{}
+	for i := 1; i <= 150; i++ {
+		hvs = append(hvs, Hypervisor{ID: i, Online: i != 150})
+	}
+
+	mux.HandleFunc("/settings/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		require.NotEmpty(t, r.URL.Query().Get("per_page"))
+		writeTestPage(t, w, r, "hypervisor", hvs)
+	})
+
+	events, err := NewHypervisorMonitor(client, nil).Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, 150, events[0].HypervisorID)
+	require.Equal(t, HypervisorEventOffline, events[0].Type)
+}
+
+func TestHypervisorMonitor_PollRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	online := false
+	mux.HandleFunc("/settings/hypervisors.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"hypervisor":{"id":1,"label":"hv-1","online":%t}}]`, online)
+	})
+
+	fail := true
+	var sent []HypervisorEvent
+	m := NewHypervisorMonitor(client, &HypervisorMonitorOptions{
+		Sinks: []HypervisorEventSink{
+			HypervisorEventFunc(func(ctx context.Context, e *HypervisorEvent) error {
+				if fail {
+					return fmt.Errorf("sink is down")
+				}
+				sent = append(sent, *e)
+				return nil
+			}),
+		},
+		OnError: func(error) {},
+	})
+
+	_, err := m.Poll(ctx)
+	require.Error(t, err)
+	require.Empty(t, sent)
+
+	// failed transition is redelivered
+	fail = false
+	events, err := m.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []HypervisorEvent{events[0]}, sent)
+	require.False(t, sent[0].Resolved)
+
+	online = true
+	fail = true
+	_, err = m.Poll(ctx)
+	require.Error(t, err)
+
+	fail = false
+	events, err = m.Poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.True(t, events[0].Resolved)
+
+	events, err = m.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
@@ -0,0 +1,105 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const hypervisorStatisticsBasePath string = hypervisorsBasePath + "/%d/statistics"
+
+const hypervisorStatisticsTimeLayout string = "2006-01-02 15:04:05"
+
+// HypervisorStatisticsRequest - time range of the statistics, zero values
+// are not sent, so OnApp default period is used
+type HypervisorStatisticsRequest struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Page      int
+	PerPage   int
+}
+
+type hypervisorStatisticsOptions struct {
+	StartDate string `url:"period[startdate],omitempty"`
+	EndDate   string `url:"period[enddate],omitempty"`
+	Page      int    `url:"page,omitempty"`
+	PerPage   int    `url:"per_page,omitempty"`
+}
+
+// HypervisorStatistic - CPU and memory usage of the Hypervisor at the StatTime
+type HypervisorStatistic struct {
+	ID           int     `json:"id,omitempty"`
+	HypervisorID int     `json:"hypervisor_id,omitempty"`
+	StatTime     string  `json:"stat_time,omitempty"`
+	CPUUsage     float64 `json:"cpu_usage,omitempty"`
+	CPUIdle      float64 `json:"cpu_idle,omitempty"`
+	FreeMemory   int     `json:"free_memory,omitempty"`
+	TotalMemory  int     `json:"total_memory,omitempty"`
+	CreatedAt    string  `json:"created_at,omitempty"`
+	UpdatedAt    string  `json:"updated_at,omitempty"`
+}
+
+// Time return parsed StatTime
+func (obj *HypervisorStatistic) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339, obj.StatTime)
+}
+
+// MemoryUsage return used memory in percents
+func (obj *HypervisorStatistic) MemoryUsage() float64 {
+	if obj.TotalMemory == 0 {
+		return 0
+	}
+
+	return float64(obj.TotalMemory-obj.FreeMemory) * 100 / float64(obj.TotalMemory)
+}
+
+// Statistics - List CPU and memory statistics of the Hypervisor for the period
+func (s *HypervisorsServiceOp) Statistics(ctx context.Context, id int, statRequest *HypervisorStatisticsRequest) ([]HypervisorStatistic, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	if statRequest != nil && !statRequest.StartDate.IsZero() && !statRequest.EndDate.IsZero() &&
+		statRequest.EndDate.Before(statRequest.StartDate) {
+		return nil, nil, godo.NewArgError("EndDate", "cannot be before StartDate")
+	}
+
+	opt := &hypervisorStatisticsOptions{}
+	if statRequest != nil {
+		opt.Page = statRequest.Page
+		opt.PerPage = statRequest.PerPage
+		if !statRequest.StartDate.IsZero() {
+			opt.StartDate = statRequest.StartDate.Format(hypervisorStatisticsTimeLayout)
+		}
+		if !statRequest.EndDate.IsZero() {
+			opt.EndDate = statRequest.EndDate.Format(hypervisorStatisticsTimeLayout)
+		}
+	}
+
+	path := fmt.Sprintf(hypervisorStatisticsBasePath, id) + apiFormat
+	path, err := addOptions(path, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []map[string]HypervisorStatistic
+	resp, err := s.client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	arr := make([]HypervisorStatistic, len(out))
+	for i := range arr {
+		arr[i] = out[i]["hypervisor_statistic"]
+	}
+
+	return arr, resp, err
+}
@@ -0,0 +1,37 @@
+package onappgo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHypervisors_Statistics(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/hypervisors/1/statistics.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		require.Equal(t, "2020-04-01 00:00:00", r.URL.Query().Get("period[startdate]"))
+		require.Equal(t, "2020-04-02 12:30:00", r.URL.Query().Get("period[enddate]"))
+
+		fmt.Fprint(w, `[{"hypervisor_statistic":{"id":1,"hypervisor_id":1,"stat_time":"2020-04-01T01:00:00Z","cpu_usage":12.5,"free_memory":1024,"total_memory":4096}}]`)
+	})
+
+	statRequest := &HypervisorStatisticsRequest{
+		StartDate: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2020, 4, 2, 12, 30, 0, 0, time.UTC),
+	}
+	stats, _, err := client.Hypervisors.Statistics(ctx, 1, statRequest)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Equal(t, 12.5, stats[0].CPUUsage)
+	require.Equal(t, 75.0, stats[0].MemoryUsage())
+
+	statRequest.StartDate, statRequest.EndDate = statRequest.EndDate, statRequest.StartDate
+	_, _, err = client.Hypervisors.Statistics(ctx, 1, statRequest)
+	require.Error(t, err)
+}
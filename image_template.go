@@ -11,6 +11,9 @@ import (
 
 const imageTemplatesBasePath string = "templates"
 
+// ImageTemplateActive - state of the ImageTemplate ready to be used
+const ImageTemplateActive string = "active"
+
 // ImageTemplatesService is an interface for interfacing with the ImageTemplate
 // endpoints of the OnApp API
 // See: https://docs.onapp.com/apim/latest/templates
@@ -214,8 +217,11 @@ func (s *ImageTemplatesServiceOp) Edit(ctx context.Context, id int, editRequest
 	}
 
 	path := fmt.Sprintf("%s/%d%s", imageTemplatesBasePath, id, apiFormat)
+	rootRequest := &imageTemplateEditRequestRoot{
+		ImageTemplateEditRequest: editRequest,
+	}
 
-	req, err := s.client.NewRequest(ctx, http.MethodPut, path, editRequest)
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
 	if err != nil {
 		return nil, err
 	}
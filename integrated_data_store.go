@@ -28,6 +28,12 @@ type IntegratedDataStoresService interface {
 	// Move next functions to the IntegratedDataStoreActionsService
 	StorageNodes(context.Context, int) (*StorageNodes, *Response, error)
 	BackendNodes(context.Context, int) (*BackendNodes, *Response, error)
+
+	Node(context.Context, int, string) (*Node, *Response, error)
+	VDisks(context.Context, int, string) ([]VDisk, *Response, error)
+	RepairVDisk(context.Context, int, string, string) (*Response, error)
+	Rebalance(context.Context, int, string) (*Response, error)
+	Health(context.Context, int) ([]IntegratedDataStoreHealth, *Response, error)
 }
 
 // IntegratedDataStoresServiceOp handles communication with the Data Store related methods of the
@@ -38,8 +44,17 @@ type IntegratedDataStoresServiceOp struct {
 
 var _ IntegratedDataStoresService = &IntegratedDataStoresServiceOp{}
 
+// Node - storage node of the integrated storage
 type Node struct {
-	ID string `json:"id,omitempty"`
+	ID           string `json:"id,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Type         string `json:"type,omitempty"`
+	HostID       int    `json:"host_id,omitempty"`
+	HypervisorID int    `json:"hypervisor_id,omitempty"`
+	DevicePath   string `json:"device_path,omitempty"`
+	Total        int64  `json:"total,omitempty"`
+	Free         int64  `json:"free,omitempty"`
+	Utilization  int    `json:"utilization,omitempty"`
 }
 
 type Nodes struct {
@@ -0,0 +1,261 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+const integratedDataStoreVDisksBasePath string = integratedDataStoresBasePath + "/%s/vdisks"
+const integratedDataStoreVDiskRepairBasePath string = integratedDataStoreVDisksBasePath + "/%s/repair"
+const integratedDataStoreRebalanceBasePath string = integratedDataStoresBasePath + "/%s/rebalance"
+
+// Storage node statuses
+const (
+	NodeActive   = "ACTIVE"
+	NodeInactive = "INACTIVE"
+	NodeOffline  = "OFFLINE"
+)
+
+// VDiskMember - stripe of the vDisk replica placed on the storage node
+type VDiskMember struct {
+	NodeID  string `json:"node_id,omitempty"`
+	Replica int    `json:"replica,omitempty"`
+	Status  string `json:"status,omitempty"`
+
+	// Member is not fully synchronized
+	Partial bool `json:"partial,bool"`
+}
+
+// VDisk - virtual disk of the integrated data store
+type VDisk struct {
+	ID       string        `json:"id,omitempty"`
+	Name     string        `json:"name,omitempty"`
+	Size     int64         `json:"size,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Degraded bool          `json:"degraded,bool"`
+	Replicas int           `json:"replicas,omitempty"`
+	Stripes  int           `json:"stripes,omitempty"`
+	Members  []VDiskMember `json:"members,omitempty"`
+}
+
+// IntegratedDataStoreHealth - health of the integrated data store
+type IntegratedDataStoreHealth struct {
+	DataStore IntegratedDataStores
+
+	// Nodes of the data store and the number of active ones
+	Nodes        []Node
+	HealthyNodes int
+
+	DegradedVDisks []VDisk
+
+	// Least number of healthy replicas among vDisks, if there are no vDisks
+	// then number of replicas which can be placed on healthy nodes
+	HealthyReplicas int
+}
+
+type nodeRoot struct {
+	Node *Node `json:"node"`
+}
+
+// Healthy check if node is active
+func (obj *Node) Healthy() bool {
+	return strings.EqualFold(obj.Status, NodeActive)
+}
+
+// Used return used space of the node
+func (obj *Node) Used() int64 {
+	return obj.Total - obj.Free
+}
+
+// HealthyReplicas return number of replicas which have all stripes on the
+// healthy nodes and fully synchronized
+func (obj *VDisk) HealthyReplicas(nodes map[string]Node) int {
+	broken := make(map[int]bool)
+	replicas := make(map[int]bool)
+
+	for _, m := range obj.Members {
+		replicas[m.Replica] = true
+
+		node, ok := nodes[m.NodeID]
+		if m.Partial || !ok || !node.Healthy() {
+			broken[m.Replica] = true
+		}
+	}
+
+	return len(replicas) - len(broken)
+}
+
+// Degraded check if data store has less healthy replicas than configured
+func (obj *IntegratedDataStoreHealth) Degraded() bool {
+	return obj.HealthyReplicas < obj.DataStore.Replicas || len(obj.DegradedVDisks) > 0
+}
+
+// EvaluateIntegratedDataStoreHealth summarize health of the data store by
+// states of the storage nodes and vDisks
+func EvaluateIntegratedDataStoreHealth(ds *IntegratedDataStores, nodes []Node, vdisks []VDisk) *IntegratedDataStoreHealth {
+	byID := make(map[string]Node, len(nodes))
+	for _, v := range nodes {
+		byID[v.ID] = v
+	}
+
+	res := &IntegratedDataStoreHealth{
+		DataStore: *ds,
+	}
+
+	for _, v := range ds.Nodes {
+		node, ok := byID[v.Node.ID]
+		if !ok {
+			node = Node{ID: v.Node.ID, Status: NodeOffline}
+		}
+
+		res.Nodes = append(res.Nodes, node)
+		if node.Healthy() {
+			res.HealthyNodes++
+		}
+	}
+
+	if len(vdisks) == 0 {
+		stripes := ds.Stripes
+		if stripes < 1 {
+			stripes = 1
+		}
+		res.HealthyReplicas = res.HealthyNodes / stripes
+
+		return res
+	}
+
+	res.HealthyReplicas = -1
+	for _, v := range vdisks {
+		healthy := v.HealthyReplicas(byID)
+		if res.HealthyReplicas < 0 || healthy < res.HealthyReplicas {
+			res.HealthyReplicas = healthy
+		}
+
+		replicas := v.Replicas
+		if replicas == 0 {
+			replicas = ds.Replicas
+		}
+
+		if v.Degraded || healthy < replicas {
+			res.DegradedVDisks = append(res.DegradedVDisks, v)
+		}
+	}
+
+	return res
+}
+
+// Node - get details of the storage node of the HypervisorGroup
+func (s *IntegratedDataStoresServiceOp) Node(ctx context.Context, hvgID int, id string) (*Node, *Response, error) {
+	if hvgID < 1 || id == "" {
+		return nil, nil, godo.NewArgError("hvgID or id", "cannot be empty or less than 1")
+	}
+
+	path := fmt.Sprintf(integratedDataStoreStorageNodesBasePath, hvgID)
+	path = fmt.Sprintf("%s/%s%s", path, id, apiFormat)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(nodeRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Node, resp, err
+}
+
+// VDisks - List vDisks of the integrated data store
+func (s *IntegratedDataStoresServiceOp) VDisks(ctx context.Context, hvgID int, id string) ([]VDisk, *Response, error) {
+	if hvgID < 1 || id == "" {
+		return nil, nil, godo.NewArgError("hvgID or id", "cannot be empty or less than 1")
+	}
+
+	path := fmt.Sprintf(integratedDataStoreVDisksBasePath, hvgID, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []map[string]VDisk
+	resp, err := s.client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	arr := make([]VDisk, len(out))
+	for i := range arr {
+		arr[i] = out[i]["vdisk"]
+	}
+
+	return arr, resp, err
+}
+
+// RepairVDisk - resynchronize degraded vDisk of the integrated data store
+func (s *IntegratedDataStoresServiceOp) RepairVDisk(ctx context.Context, hvgID int, id string, vdiskID string) (*Response, error) {
+	if hvgID < 1 || id == "" || vdiskID == "" {
+		return nil, godo.NewArgError("hvgID, id or vdiskID", "cannot be empty or less than 1")
+	}
+
+	path := fmt.Sprintf(integratedDataStoreVDiskRepairBasePath, hvgID, id, vdiskID) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("IntegratedDataStores [RepairVDisk]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Rebalance - redistribute content of the integrated data store across its nodes
+func (s *IntegratedDataStoresServiceOp) Rebalance(ctx context.Context, hvgID int, id string) (*Response, error) {
+	if hvgID < 1 || id == "" {
+		return nil, godo.NewArgError("hvgID or id", "cannot be empty or less than 1")
+	}
+
+	path := fmt.Sprintf(integratedDataStoreRebalanceBasePath, hvgID, id) + apiFormat
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("IntegratedDataStores [Rebalance]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Health - summarize health of all integrated data stores of the HypervisorGroup
+func (s *IntegratedDataStoresServiceOp) Health(ctx context.Context, hvgID int) ([]IntegratedDataStoreHealth, *Response, error) {
+	dss, resp, err := s.List(ctx, hvgID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	lst, resp, err := s.StorageNodes(ctx, hvgID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	nodes := make([]Node, len(*lst))
+	for i, v := range *lst {
+		nodes[i] = v.Node
+	}
+
+	res := make([]IntegratedDataStoreHealth, len(dss))
+	for i := range dss {
+		vdisks, r, err := s.VDisks(ctx, hvgID, dss[i].ID)
+		if err != nil {
+			return nil, r, err
+		}
+		resp = r
+
+		res[i] = *EvaluateIntegratedDataStoreHealth(&dss[i], nodes, vdisks)
+	}
+
+	return res, resp, nil
+}
@@ -0,0 +1,67 @@
+package onappgo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegratedDataStores_Health(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/storage/1/data_stores.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"data_store":{"id":"ds1","replicas":2,"stripes":1,"nodes":[{"node":{"id":"n1"}},{"node":{"id":"n2"}},{"node":{"id":"n3"}},{"node":{"id":"n4"}}]}},
+			{"data_store":{"id":"ds2","replicas":1,"stripes":2,"nodes":[{"node":{"id":"n3"}},{"node":{"id":"n4"}}]}}]`)
+	})
+
+	mux.HandleFunc("/storage/1/nodes.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"node":{"id":"n1","status":"ACTIVE","total":100,"free":40}},{"node":{"id":"n2","status":"OFFLINE"}},
+			{"node":{"id":"n3","status":"ACTIVE"}},{"node":{"id":"n4","status":"ACTIVE"}}]`)
+	})
+
+	mux.HandleFunc("/storage/1/data_stores/ds1/vdisks.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"vdisk":{"id":"v1","members":[{"node_id":"n1","replica":0},{"node_id":"n2","replica":1}]}},
+			{"vdisk":{"id":"v2","members":[{"node_id":"n3","replica":0},{"node_id":"n4","replica":1}]}},
+			{"vdisk":{"id":"v3","members":[{"node_id":"n3","replica":0},{"node_id":"n4","replica":1,"partial":true}]}}]`)
+	})
+
+	mux.HandleFunc("/storage/1/data_stores/ds2/vdisks.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	health, _, err := client.IntegratedDataStores.Health(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, health, 2)
+
+	require.Equal(t, 3, health[0].HealthyNodes)
+	require.Equal(t, 1, health[0].HealthyReplicas)
+	require.True(t, health[0].Degraded())
+	require.Len(t, health[0].DegradedVDisks, 2)
+	require.Equal(t, "v1", health[0].DegradedVDisks[0].ID)
+	require.Equal(t, "v3", health[0].DegradedVDisks[1].ID)
+	require.Equal(t, int64(60), health[0].Nodes[0].Used())
+
+	require.Equal(t, 1, health[1].HealthyReplicas)
+	require.False(t, health[1].Degraded())
+}
+
+func TestIntegratedDataStores_RepairVDisk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	repaired := false
+	mux.HandleFunc("/storage/1/data_stores/ds1/vdisks/v1/repair.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		repaired = true
+	})
+
+	_, err := client.IntegratedDataStores.RepairVDisk(ctx, 1, "ds1", "v1")
+	require.NoError(t, err)
+	require.True(t, repaired)
+
+	_, err = client.IntegratedDataStores.RepairVDisk(ctx, 1, "ds1", "")
+	require.Error(t, err)
+}
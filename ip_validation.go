@@ -0,0 +1,173 @@
+package onappgo
+
+import (
+	"bytes"
+	"context"
+	"net"
+
+	"github.com/digitalocean/godo"
+)
+
+// Validate IPNetCreateRequest before sending it to the OnApp API. Returned
+// error is *ValidationError.
+func (d *IPNetCreateRequest) Validate() error {
+	verr := &ValidationError{}
+
+	ipNet := IPNet{
+		NetworkAddress: d.NetworkAddress,
+		NetworkMask:    d.NetworkMask,
+	}
+
+	ip := net.ParseIP(d.NetworkAddress)
+	cidr, err := ipNet.CIDR()
+	switch {
+	case ip == nil:
+		verr.Add("network_address", "%q is not an IP address", d.NetworkAddress)
+	case err != nil:
+		verr.Add("network_mask", "%d is out of range for the IPv%d address", d.NetworkMask, ipVersion(ip))
+	case !cidr.IP.Equal(ip):
+		verr.Add("network_address", "%s is not a network address of the /%d net, should be %s", ip, d.NetworkMask, cidr.IP)
+	}
+
+	if d.DefaultGateway != "" {
+		validateGateway(verr, d.DefaultGateway, d.GatewayOutsideIPNet, ip, cidr)
+	}
+
+	return verr.errOrNil()
+}
+
+// Validate IPRangeCreateRequest against parent IPNet and existing IPRanges of
+// the IPNet before sending it to the OnApp API. Returned error is
+// *ValidationError.
+func (d *IPRangeCreateRequest) Validate(ipNet *IPNet, existing []IPRange) error {
+	verr := &ValidationError{}
+
+	var cidr *net.IPNet
+	var family net.IP
+	if ipNet != nil {
+		var err error
+		cidr, err = ipNet.CIDR()
+		if err != nil {
+			verr.Add("ip_net", "%s", err)
+		} else {
+			family = cidr.IP
+		}
+	}
+
+	start := normalizeIP(net.ParseIP(d.StartAddress))
+	end := normalizeIP(net.ParseIP(d.EndAddress))
+
+	if start == nil {
+		verr.Add("start_address", "%q is not an IP address", d.StartAddress)
+	}
+
+	if end == nil {
+		verr.Add("end_address", "%q is not an IP address", d.EndAddress)
+	}
+
+	if start != nil && end != nil {
+		switch {
+		case len(start) != len(end):
+			verr.Add("end_address", "%s is IPv%d address, but start address %s is IPv%d one", end, ipVersion(end), start, ipVersion(start))
+		case bytes.Compare(start, end) > 0:
+			verr.Add("end_address", "%s is less than start address %s", end, start)
+		}
+	}
+
+	if cidr != nil {
+		for field, ip := range map[string]net.IP{"start_address": start, "end_address": end} {
+			if ip == nil {
+				continue
+			}
+
+			if len(ip) != len(family) {
+				verr.Add(field, "%s is IPv%d address, but IPNet is IPv%d one", ip, ipVersion(ip), ipVersion(family))
+			} else if !cidr.Contains(ip) {
+				verr.Add(field, "%s is outside of the IPNet %s", ip, cidr)
+			}
+		}
+	}
+
+	if d.DefaultGateway != "" {
+		validateGateway(verr, d.DefaultGateway, d.GatewayOutsideIPNet, family, cidr)
+	}
+
+	if start != nil && end != nil && !verr.Has("start_address") && !verr.Has("end_address") {
+		r := IPRange{StartAddress: d.StartAddress, EndAddress: d.EndAddress}
+		for i := range existing {
+			if ipRangesOverlap(&r, &existing[i]) {
+				verr.Add("base", "range %s - %s overlaps IPRange [%d] %s - %s",
+					start, end, existing[i].ID, existing[i].StartAddress, existing[i].EndAddress)
+			}
+		}
+	}
+
+	return verr.errOrNil()
+}
+
+// Validate IPRangeCreateRequest against IPNet of the Network and its existing
+// IPRanges fetched from the OnApp API. Returned error is *ValidationError if
+// the request is wrong.
+func (s *IPRangesServiceOp) Validate(ctx context.Context, net int, ipnet int, createRequest *IPRangeCreateRequest) (*Response, error) {
+	if createRequest == nil {
+		return nil, godo.NewArgError("IPRange createRequest", "cannot be nil")
+	}
+
+	if net < 1 || ipnet < 1 {
+		return nil, godo.NewArgError("net || ipnet", "cannot be less than 1")
+	}
+
+	ipNet, resp, err := s.client.IPNets.Get(ctx, net, ipnet)
+	if err != nil {
+		return resp, err
+	}
+
+	var existing []IPRange
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, net, ipnet, opt)
+		existing = append(existing, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, createRequest.Validate(ipNet, existing)
+}
+
+func validateGateway(verr *ValidationError, gateway string, outside bool, family net.IP, cidr *net.IPNet) {
+	gw := normalizeIP(net.ParseIP(gateway))
+	switch {
+	case gw == nil:
+		verr.Add("default_gateway", "%q is not an IP address", gateway)
+	case family != nil && len(normalizeIP(family)) != len(gw):
+		verr.Add("default_gateway", "%s is IPv%d address, but IPNet is IPv%d one", gw, ipVersion(gw), ipVersion(family))
+	case cidr == nil:
+	case !outside && !cidr.Contains(gw):
+		verr.Add("default_gateway", "%s is outside of the IPNet %s, set gateway_outside_ip_net to use it", gw, cidr)
+	case !outside && gw.Equal(cidr.IP):
+		verr.Add("default_gateway", "%s is the network address of the IPNet %s", gw, cidr)
+	}
+}
+
+func ipRangesOverlap(a *IPRange, b *IPRange) bool {
+	aStart, aEnd, err := a.Bounds()
+	if err != nil {
+		return false
+	}
+
+	bStart, bEnd, err := b.Bounds()
+	if err != nil || len(aStart) != len(bStart) {
+		return false
+	}
+
+	return bytes.Compare(aStart, bEnd) <= 0 && bytes.Compare(bStart, aEnd) <= 0
+}
+
+func ipVersion(ip net.IP) int {
+	if ip.To4() != nil {
+		return 4
+	}
+
+	return 6
+}
@@ -0,0 +1,92 @@
+package onappgo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validationErrors(t *testing.T, err error) map[string][]string {
+	require.Error(t, err)
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok, "expected *ValidationError, got %T", err)
+
+	return verr.Errors
+}
+
+func TestIPNetCreateRequest_Validate(t *testing.T) {
+	ok := &IPNetCreateRequest{NetworkAddress: "10.0.0.0", NetworkMask: 24, DefaultGateway: "10.0.0.1"}
+	require.NoError(t, ok.Validate())
+
+	outside := &IPNetCreateRequest{NetworkAddress: "10.0.0.0", NetworkMask: 24, DefaultGateway: "10.0.1.1", GatewayOutsideIPNet: true}
+	require.NoError(t, outside.Validate())
+
+	errs := validationErrors(t, (&IPNetCreateRequest{NetworkAddress: "10.0.0.0", NetworkMask: 33}).Validate())
+	require.Contains(t, errs, "network_mask")
+
+	errs = validationErrors(t, (&IPNetCreateRequest{NetworkAddress: "10.0.0.5", NetworkMask: 24}).Validate())
+	require.Contains(t, errs, "network_address")
+
+	errs = validationErrors(t, (&IPNetCreateRequest{NetworkAddress: "10.0.0.0", NetworkMask: 24, DefaultGateway: "10.0.1.1"}).Validate())
+	require.Contains(t, errs, "default_gateway")
+
+	errs = validationErrors(t, (&IPNetCreateRequest{NetworkAddress: "2001:db8::", NetworkMask: 64, DefaultGateway: "10.0.0.1"}).Validate())
+	require.Contains(t, errs, "default_gateway")
+}
+
+func TestIPRangeCreateRequest_Validate(t *testing.T) {
+	ipNet := &IPNet{ID: 1, NetworkAddress: "10.0.0.0", NetworkMask: 24}
+	existing := []IPRange{{ID: 5, StartAddress: "10.0.0.100", EndAddress: "10.0.0.150"}}
+
+	ok := &IPRangeCreateRequest{StartAddress: "10.0.0.10", EndAddress: "10.0.0.99", DefaultGateway: "10.0.0.1"}
+	require.NoError(t, ok.Validate(ipNet, existing))
+
+	errs := validationErrors(t, (&IPRangeCreateRequest{StartAddress: "10.0.0.20", EndAddress: "10.0.0.10"}).Validate(ipNet, nil))
+	require.Contains(t, errs, "end_address")
+
+	errs = validationErrors(t, (&IPRangeCreateRequest{StartAddress: "10.0.0.200", EndAddress: "10.0.1.10"}).Validate(ipNet, nil))
+	require.Contains(t, errs, "end_address")
+	require.NotContains(t, errs, "start_address")
+
+	errs = validationErrors(t, (&IPRangeCreateRequest{StartAddress: "2001:db8::1", EndAddress: "2001:db8::10"}).Validate(ipNet, nil))
+	require.Contains(t, errs, "start_address")
+	require.Contains(t, errs, "end_address")
+
+	errs = validationErrors(t, (&IPRangeCreateRequest{StartAddress: "10.0.0.140", EndAddress: "10.0.0.160"}).Validate(ipNet, existing))
+	require.Contains(t, errs, "base")
+
+	errs = validationErrors(t, (&IPRangeCreateRequest{StartAddress: "10.0.0.10", EndAddress: "10.0.0.20", DefaultGateway: "10.0.5.1"}).Validate(ipNet, nil))
+	require.Contains(t, errs, "default_gateway")
+}
+
+func TestIPRanges_Validate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"ip_net":{"id":2,"network_address":"10.0.0.0","network_mask":16}}`)
+	})
+
+	// the last range is on the second page
+	var ranges []interface{}
+	for i := 1; i <= 150; i++ {
+		ranges = append(ranges, IPRange{ID: i, StartAddress: fmt.Sprintf("10.0.%d.0", i), EndAddress: fmt.Sprintf("10.0.%d.255", i)})
+	}
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2/ip_ranges.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "ip_range", ranges)
+	})
+
+	_, err := client.IPRanges.Validate(ctx, 1, 2, &IPRangeCreateRequest{StartAddress: "10.0.150.10", EndAddress: "10.0.151.10", DefaultGateway: "10.0.0.1"})
+	require.Contains(t, validationErrors(t, err), "base")
+
+	_, err = client.IPRanges.Validate(ctx, 1, 2, &IPRangeCreateRequest{StartAddress: "10.0.151.10", EndAddress: "10.0.151.20", DefaultGateway: "10.0.0.1"})
+	require.NoError(t, err)
+
+	_, err = client.IPRanges.Validate(ctx, 1, 0, &IPRangeCreateRequest{})
+	require.Error(t, err)
+}
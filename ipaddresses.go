@@ -3,7 +3,7 @@ package onappgo
 import (
 	"context"
 	"fmt"
-	// "log"
+	"log"
 	"net/http"
 
 	"github.com/digitalocean/godo"
@@ -20,11 +20,12 @@ const ipAddressesUnassignUserBasePath string = "settings/networks/%d/ip_addresse
 type IPAddressesService interface {
 	List(context.Context, int, *ListOptions) ([]IPAddressJoin, *Response, error)
 
-	// AssignVS(context.Context, int, *AssignIPAddress) (*IPAddress, *Response, error)
+	AssignVS(context.Context, int, *AssignIPAddress) (*IPAddressJoin, *Response, error)
 	// UnassingVS(context.Context, int) (*Response, error)
 
-	// AssignUser(context.Context, int, *AssignIPAddress) (*IPAddress, *Response, error)
-	// UnassingUser(context.Context, int) (*Response, error)
+	AssignUser(context.Context, int, *AssignIPAddress) (*IPAddress, *Response, error)
+	UnassignUser(context.Context, int, *AssignIPAddress) (*Response, error)
+	ListUserOwned(context.Context, int) ([]IPAddress, *Response, error)
 }
 
 // IPAddressesServiceOp handles communication with the IPAddresses related methods of the
@@ -76,7 +77,9 @@ type IPAddresses struct {
 	IPAddress IPAddress `json:"ip_address,omitempty"`
 }
 
-// AssignIPAddress - used for assign IPAddress to the VirtualMachine or User
+// AssignIPAddress - used for assign IPAddress to the VirtualMachine or User.
+// OwnIP is set to 1 to assign the IPAddress owned by the User to the
+// VirtualMachine, UsedIP is set to 1 to assign already used IPAddress.
 type AssignIPAddress struct {
 	Address            string `json:"address,omitempty"`
 	IPNetID            int    `json:"ip_net_id,omitempty"`
@@ -85,6 +88,7 @@ type AssignIPAddress struct {
 	NetworkInterfaceID int    `json:"network_interface_id,omitempty"`
 	OwnIP              int    `json:"own_ip,omitempty"`
 	UsedIP             int    `json:"used_ip,omitempty"`
+	UserID             int    `json:"user_id,omitempty"`
 }
 
 // List all IPAddress
@@ -117,3 +121,118 @@ func (s *IPAddressesServiceOp) List(ctx context.Context, id int, opt *ListOption
 
 	return arr, resp, err
 }
+
+type assignIPAddressRoot struct {
+	AssignIPAddress *AssignIPAddress `json:"ip_address"`
+}
+
+type ipAddressJoinRoot struct {
+	IPAddressJoin *IPAddressJoin `json:"ip_address_join"`
+}
+
+// AssignVS - Assign IPAddress to the NetworkInterface of the VirtualMachine
+func (s *IPAddressesServiceOp) AssignVS(ctx context.Context, vmID int, assignRequest *AssignIPAddress) (*IPAddressJoin, *Response, error) {
+	if vmID < 1 {
+		return nil, nil, godo.NewArgError("vmID", "cannot be less than 1")
+	}
+
+	if assignRequest == nil {
+		return nil, nil, godo.NewArgError("IPAddress assignRequest", "cannot be nil")
+	}
+
+	if assignRequest.NetworkInterfaceID < 1 {
+		return nil, nil, godo.NewArgError("NetworkInterfaceID", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(ipAddressesVSBasePath, vmID) + apiFormat
+	rootRequest := &assignIPAddressRoot{
+		AssignIPAddress: assignRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("IPAddress [AssignVS] req: ", req)
+
+	root := new(ipAddressJoinRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.IPAddressJoin, resp, err
+}
+
+// AssignUser - Assign IPAddress of the Network to the User, so the User owns it
+func (s *IPAddressesServiceOp) AssignUser(ctx context.Context, networkID int, assignRequest *AssignIPAddress) (*IPAddress, *Response, error) {
+	if networkID < 1 {
+		return nil, nil, godo.NewArgError("networkID", "cannot be less than 1")
+	}
+
+	if assignRequest == nil {
+		return nil, nil, godo.NewArgError("IPAddress assignRequest", "cannot be nil")
+	}
+
+	if assignRequest.UserID < 1 {
+		return nil, nil, godo.NewArgError("UserID", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(ipAddressesAssignUserBasePath, networkID) + apiFormat
+	rootRequest := &assignIPAddressRoot{
+		AssignIPAddress: assignRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("IPAddress [AssignUser] req: ", req)
+
+	root := new(IPAddresses)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &root.IPAddress, resp, err
+}
+
+// UnassignUser - Release IPAddress of the Network owned by the User
+func (s *IPAddressesServiceOp) UnassignUser(ctx context.Context, networkID int, unassignRequest *AssignIPAddress) (*Response, error) {
+	if networkID < 1 {
+		return nil, godo.NewArgError("networkID", "cannot be less than 1")
+	}
+
+	if unassignRequest == nil {
+		return nil, godo.NewArgError("IPAddress unassignRequest", "cannot be nil")
+	}
+
+	path := fmt.Sprintf(ipAddressesUnassignUserBasePath, networkID) + apiFormat
+	rootRequest := &assignIPAddressRoot{
+		AssignIPAddress: unassignRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("IPAddress [UnassignUser] req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ListUserOwned - List IPAddresses owned by the User
+func (s *IPAddressesServiceOp) ListUserOwned(ctx context.Context, userID int) ([]IPAddress, *Response, error) {
+	user, resp, err := s.client.Users.Get(ctx, userID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	arr := make([]IPAddress, len(user.UsedIPAddresses))
+	for i := range arr {
+		arr[i] = user.UsedIPAddresses[i].IPAddress
+	}
+
+	return arr, resp, err
+}
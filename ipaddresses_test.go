@@ -0,0 +1,178 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAddresses_AssignUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/networks/2/ip_addresses/assign.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		var got map[string]AssignIPAddress
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, UserID: 7}, got["ip_address"])
+
+		fmt.Fprint(w, `{"ip_address":{"id":11,"address":"10.0.0.5","network_id":2,"user_id":7}}`)
+	})
+
+	ip, _, err := client.IPAddresses.AssignUser(ctx, 2, &AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, UserID: 7})
+	require.NoError(t, err)
+	require.Equal(t, 11, ip.ID)
+
+	_, _, err = client.IPAddresses.AssignUser(ctx, 2, &AssignIPAddress{Address: "10.0.0.5"})
+	require.Error(t, err)
+}
+
+func TestUsers_DeleteReleaseIPAddresses(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/7.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"user":{"id":7,"used_ip_addresses":[
+				{"ip_address":{"id":11,"address":"10.0.0.5","network_id":2,"ip_net_id":3}},
+				{"ip_address":{"id":12,"address":"10.1.0.5","network_id":4,"ip_net_id":5}}]}}`)
+		case http.MethodDelete:
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	var released []string
+	for _, network := range []int{2, 4} {
+		mux.HandleFunc(fmt.Sprintf("/settings/networks/%d/ip_addresses/unassign.json", network), func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+
+			var got map[string]AssignIPAddress
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			require.Equal(t, 7, got["ip_address"].UserID)
+			released = append(released, got["ip_address"].Address)
+		})
+	}
+
+	_, err := client.Users.Delete(ctx, 7, &UserDeleteOptions{ReleaseIPAddresses: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.5", "10.1.0.5"}, released)
+}
+
+func TestUsers_DeleteParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var queries, bodies []string
+	mux.HandleFunc("/users/7.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		queries = append(queries, r.URL.RawQuery)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	params := &struct {
+		Migrate int `url:"migrate"`
+	}{Migrate: 1}
+
+	_, err := client.Users.Delete(ctx, 7, nil)
+	require.NoError(t, err)
+
+	_, err = client.Users.Delete(ctx, 7, &UserDeleteRequest{})
+	require.NoError(t, err)
+
+	_, err = client.Users.Delete(ctx, 7, &UserDeleteOptions{Params: &UserDeleteRequest{}})
+	require.NoError(t, err)
+
+	_, err = client.Users.Delete(ctx, 7, &UserDeleteOptions{Params: params})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"", "", "", "migrate=1"}, queries)
+	require.JSONEq(t, `{"force":1}`, bodies[0])
+	require.JSONEq(t, `{}`, bodies[1])
+	require.JSONEq(t, `{}`, bodies[2])
+	require.JSONEq(t, `{"force":1}`, bodies[3])
+}
+
+func TestIPAddresses_AssignVS(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/3/ip_addresses.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		var got map[string]AssignIPAddress
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, NetworkInterfaceID: 4}, got["ip_address"])
+
+		fmt.Fprint(w, `{"ip_address_join":{"id":21,"network_interface_id":4,"ip_address":{"id":11,"address":"10.0.0.5"}}}`)
+	})
+
+	join, _, err := client.IPAddresses.AssignVS(ctx, 3, &AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, NetworkInterfaceID: 4})
+	require.NoError(t, err)
+	require.Equal(t, 21, join.ID)
+	require.Equal(t, "10.0.0.5", join.IPAddress.Address)
+
+	_, _, err = client.IPAddresses.AssignVS(ctx, 3, &AssignIPAddress{Address: "10.0.0.5"})
+	require.Error(t, err)
+
+	_, _, err = client.IPAddresses.AssignVS(ctx, 0, &AssignIPAddress{NetworkInterfaceID: 4})
+	require.Error(t, err)
+}
+
+func TestIPAddresses_UnassignUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/networks/2/ip_addresses/unassign.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		var got map[string]AssignIPAddress
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, UserID: 7}, got["ip_address"])
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := client.IPAddresses.UnassignUser(ctx, 2, &AssignIPAddress{Address: "10.0.0.5", IPNetID: 3, UserID: 7})
+	require.NoError(t, err)
+
+	_, err = client.IPAddresses.UnassignUser(ctx, 2, nil)
+	require.Error(t, err)
+
+	_, err = client.IPAddresses.UnassignUser(ctx, 0, &AssignIPAddress{})
+	require.Error(t, err)
+}
+
+func TestIPAddresses_ListUserOwned(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/7.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"user":{"id":7,"used_ip_addresses":[
+			{"ip_address":{"id":11,"address":"10.0.0.5","network_id":2}},
+			{"ip_address":{"id":12,"address":"10.1.0.5","network_id":4}}]}}`)
+	})
+
+	mux.HandleFunc("/users/8.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"user":{"id":8}}`)
+	})
+
+	lst, _, err := client.IPAddresses.ListUserOwned(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, lst, 2)
+	require.Equal(t, "10.0.0.5", lst[0].Address)
+	require.Equal(t, 4, lst[1].NetworkID)
+
+	lst, _, err = client.IPAddresses.ListUserOwned(ctx, 8)
+	require.NoError(t, err)
+	require.Empty(t, lst)
+}
@@ -0,0 +1,553 @@
+package onappgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+
+	"github.com/digitalocean/godo"
+)
+
+const ipRangeAddressesBasePath string = ipRangesBasePath + "/%d/ip_addresses"
+
+// IPAMService is an interface for the IP address management over the IPNets
+// and IPRanges of the Network. Addresses are allocated by the OnApp on the
+// assignment, so every address of the IPRange which is not listed in the
+// IPRange addresses and is not a gateway is treated as free.
+type IPAMService interface {
+	UsedAddresses(context.Context, int, int, int) ([]IPAddress, *Response, error)
+	FreeAddresses(context.Context, int, int, int, int) ([]string, *Response, error)
+	FreeIPNetAddresses(context.Context, int, int, int) ([]string, *Response, error)
+	CountFreeIPNetAddresses(context.Context, int, int) (*big.Int, *Response, error)
+	Overlaps(context.Context, int) ([]IPNetOverlap, *Response, error)
+	Reserve(context.Context, int, int, string) (*IPAddress, *Response, error)
+	AssignNext(context.Context, int, int, bool) (*IPAddressJoin, *Response, error)
+}
+
+// IPAMServiceOp handles communication with the IPAM related methods of the
+// OnApp API.
+type IPAMServiceOp struct {
+	client *Client
+}
+
+var _ IPAMService = &IPAMServiceOp{}
+
+// IPNetOverlap - pair of IPNets of the same Network with overlapped addresses
+type IPNetOverlap struct {
+	IPNet   IPNet
+	Overlap IPNet
+}
+
+// CIDR return network of the IPNet
+func (obj *IPNet) CIDR() (*net.IPNet, error) {
+	ip := net.ParseIP(obj.NetworkAddress)
+	if ip == nil {
+		return nil, fmt.Errorf("IPNet [%d] has wrong network address %q", obj.ID, obj.NetworkAddress)
+	}
+
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bits = 8 * net.IPv4len
+	}
+
+	if obj.NetworkMask < 0 || obj.NetworkMask > bits {
+		return nil, fmt.Errorf("IPNet [%d] has wrong network mask %d", obj.ID, obj.NetworkMask)
+	}
+
+	mask := net.CIDRMask(obj.NetworkMask, bits)
+
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// Bounds return the first and the last addresses of the IPRange
+func (obj *IPRange) Bounds() (net.IP, net.IP, error) {
+	start := normalizeIP(net.ParseIP(obj.StartAddress))
+	end := normalizeIP(net.ParseIP(obj.EndAddress))
+	if start == nil || end == nil {
+		return nil, nil, fmt.Errorf("IPRange [%d] has wrong addresses %q - %q", obj.ID, obj.StartAddress, obj.EndAddress)
+	}
+
+	if len(start) != len(end) {
+		return nil, nil, fmt.Errorf("IPRange [%d] mixes IPv4 and IPv6 addresses", obj.ID)
+	}
+
+	if bytes.Compare(start, end) > 0 {
+		return nil, nil, fmt.Errorf("IPRange [%d] start address %s is greater than end address %s", obj.ID, start, end)
+	}
+
+	return start, end, nil
+}
+
+// Contains check if address belongs to the IPRange
+func (obj *IPRange) Contains(address string) bool {
+	ip := normalizeIP(net.ParseIP(address))
+	start, end, err := obj.Bounds()
+	if ip == nil || err != nil || len(ip) != len(start) {
+		return false
+	}
+
+	return bytes.Compare(start, ip) <= 0 && bytes.Compare(ip, end) <= 0
+}
+
+// FreeIPRangeAddresses return up to limit addresses of the IPRange which are
+// not used and are not the gateway. Limit less than 1 means all addresses, so
+// it should be set for IPv6 ranges.
+func FreeIPRangeAddresses(r *IPRange, used []string, limit int) ([]string, error) {
+	start, end, err := r.Bounds()
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(used)+1)
+	for _, v := range used {
+		if ip := net.ParseIP(v); ip != nil {
+			skip[ip.String()] = true
+		}
+	}
+
+	if ip := net.ParseIP(r.DefaultGateway); ip != nil {
+		skip[ip.String()] = true
+	}
+
+	var res []string
+	for ip := start; bytes.Compare(ip, end) <= 0; ip = nextIP(ip) {
+		if !skip[ip.String()] {
+			res = append(res, ip.String())
+			if limit > 0 && len(res) == limit {
+				break
+			}
+		}
+
+		// overflow of the last address
+		if ip.Equal(end) {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// CountFreeIPRangeAddresses return number of addresses of the IPRange which
+// are not used and are not the gateway, addresses are not enumerated so it is
+// safe for large IPv4 and IPv6 ranges
+func CountFreeIPRangeAddresses(r *IPRange, used []string) (*big.Int, error) {
+	start, end, err := r.Bounds()
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(big.Int).Sub(new(big.Int).SetBytes(end), new(big.Int).SetBytes(start))
+	res.Add(res, big.NewInt(1))
+
+	skip := make(map[string]bool, len(used)+1)
+	for _, v := range append(used, r.DefaultGateway) {
+		ip := net.ParseIP(v)
+		if ip == nil || skip[ip.String()] || !r.Contains(v) {
+			continue
+		}
+
+		skip[ip.String()] = true
+		res.Sub(res, big.NewInt(1))
+	}
+
+	return res, nil
+}
+
+// OverlappingIPNets return all pairs of the IPNets with overlapped addresses
+func OverlappingIPNets(nets []IPNet) ([]IPNetOverlap, error) {
+	cidrs := make([]*net.IPNet, len(nets))
+	for i := range nets {
+		cidr, err := nets[i].CIDR()
+		if err != nil {
+			return nil, err
+		}
+		cidrs[i] = cidr
+	}
+
+	var res []IPNetOverlap
+	for i := range cidrs {
+		for j := i + 1; j < len(cidrs); j++ {
+			if cidrs[i].Contains(cidrs[j].IP) || cidrs[j].Contains(cidrs[i].IP) {
+				res = append(res, IPNetOverlap{IPNet: nets[i], Overlap: nets[j]})
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func normalizeIP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip
+}
+
+func nextIP(ip net.IP) net.IP {
+	res := make(net.IP, len(ip))
+	copy(res, ip)
+
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i]++
+		if res[i] != 0 {
+			break
+		}
+	}
+
+	return res
+}
+
+// UsedAddresses - List all addresses allocated from the IPRange
+func (s *IPAMServiceOp) UsedAddresses(ctx context.Context, networkID int, ipNetID int, ipRangeID int) ([]IPAddress, *Response, error) {
+	if networkID < 1 || ipNetID < 1 || ipRangeID < 1 {
+		return nil, nil, godo.NewArgError("networkID || ipNetID || ipRangeID", "cannot be less than 1")
+	}
+
+	var res []IPAddress
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.usedAddresses(ctx, networkID, ipNetID, ipRangeID, opt)
+		res = append(res, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return res, resp, nil
+}
+
+func (s *IPAMServiceOp) usedAddresses(ctx context.Context, networkID int, ipNetID int, ipRangeID int, opt *ListOptions) ([]IPAddress, *Response, error) {
+	path := fmt.Sprintf(ipRangeAddressesBasePath, networkID, ipNetID, ipRangeID) + apiFormat
+	path, err := addOptions(path, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []map[string]IPAddress
+	resp, err := s.client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	arr := make([]IPAddress, len(out))
+	for i := range arr {
+		arr[i] = out[i]["ip_address"]
+	}
+
+	return arr, resp, err
+}
+
+// FreeAddresses - List up to limit free addresses of the IPRange
+func (s *IPAMServiceOp) FreeAddresses(ctx context.Context, networkID int, ipNetID int, ipRangeID int, limit int) ([]string, *Response, error) {
+	r, resp, err := s.client.IPRanges.Get(ctx, networkID, ipNetID, ipRangeID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return s.freeAddresses(ctx, networkID, ipNetID, r, limit)
+}
+
+// FreeIPNetAddresses - List up to limit free addresses of all IPRanges of the IPNet
+func (s *IPAMServiceOp) FreeIPNetAddresses(ctx context.Context, networkID int, ipNetID int, limit int) ([]string, *Response, error) {
+	ranges, resp, err := s.ipRanges(ctx, networkID, ipNetID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var res []string
+	for i := range ranges {
+		left := 0
+		if limit > 0 {
+			left = limit - len(res)
+		}
+
+		lst, r, err := s.freeAddresses(ctx, networkID, ipNetID, &ranges[i], left)
+		if err != nil {
+			return nil, r, err
+		}
+		resp = r
+
+		res = append(res, lst...)
+		if limit > 0 && len(res) >= limit {
+			break
+		}
+	}
+
+	return res, resp, nil
+}
+
+// CountFreeIPNetAddresses - Count free addresses of all IPRanges of the IPNet
+func (s *IPAMServiceOp) CountFreeIPNetAddresses(ctx context.Context, networkID int, ipNetID int) (*big.Int, *Response, error) {
+	ranges, resp, err := s.ipRanges(ctx, networkID, ipNetID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res := new(big.Int)
+	for i := range ranges {
+		used, r, err := s.UsedAddresses(ctx, networkID, ipNetID, ranges[i].ID)
+		if err != nil {
+			return nil, r, err
+		}
+		resp = r
+
+		addresses := make([]string, len(used))
+		for j := range used {
+			addresses[j] = used[j].Address
+		}
+
+		count, err := CountFreeIPRangeAddresses(&ranges[i], addresses)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		res.Add(res, count)
+	}
+
+	return res, resp, nil
+}
+
+func (s *IPAMServiceOp) freeAddresses(ctx context.Context, networkID int, ipNetID int, r *IPRange, limit int) ([]string, *Response, error) {
+	used, resp, err := s.UsedAddresses(ctx, networkID, ipNetID, r.ID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	addresses := make([]string, len(used))
+	for i := range used {
+		addresses[i] = used[i].Address
+	}
+
+	res, err := FreeIPRangeAddresses(r, addresses, limit)
+
+	return res, resp, err
+}
+
+// Overlaps - List pairs of the IPNets of the Network with overlapped addresses
+func (s *IPAMServiceOp) Overlaps(ctx context.Context, networkID int) ([]IPNetOverlap, *Response, error) {
+	nets, resp, err := s.ipNets(ctx, networkID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res, err := OverlappingIPNets(nets)
+
+	return res, resp, err
+}
+
+// Reserve address of the Network for the User
+func (s *IPAMServiceOp) Reserve(ctx context.Context, networkID int, userID int, address string) (*IPAddress, *Response, error) {
+	if networkID < 1 || userID < 1 {
+		return nil, nil, godo.NewArgError("networkID || userID", "cannot be less than 1")
+	}
+
+	if net.ParseIP(address) == nil {
+		return nil, nil, godo.NewArgError("address", fmt.Sprintf("%q is not an IP address", address))
+	}
+
+	ipNet, ipRange, resp, err := s.findRange(ctx, networkID, address)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	used, resp, err := s.UsedAddresses(ctx, networkID, ipNet.ID, ipRange.ID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	addresses := []string{ipRange.DefaultGateway}
+	for i := range used {
+		addresses = append(addresses, used[i].Address)
+	}
+
+	if containsAddress(addresses, address) {
+		return nil, resp, fmt.Errorf("address %s of IPRange [%d] is already used", address, ipRange.ID)
+	}
+
+	assignRequest := &AssignIPAddress{
+		Address:   address,
+		IPNetID:   ipNet.ID,
+		IPRangeID: ipRange.ID,
+		UserID:    userID,
+	}
+
+	return s.client.IPAddresses.AssignUser(ctx, networkID, assignRequest)
+}
+
+// AssignNext assign the next free IPv4 or IPv6 address of the Network to the
+// NetworkInterface of the VirtualMachine
+func (s *IPAMServiceOp) AssignNext(ctx context.Context, vmID int, networkInterfaceID int, ipv4 bool) (*IPAddressJoin, *Response, error) {
+	if vmID < 1 || networkInterfaceID < 1 {
+		return nil, nil, godo.NewArgError("vmID || networkInterfaceID", "cannot be less than 1")
+	}
+
+	networkID, resp, err := s.interfaceNetwork(ctx, vmID, networkInterfaceID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	nets, resp, err := s.ipNets(ctx, networkID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ipVersion := 6
+	if ipv4 {
+		ipVersion = 4
+	}
+
+	for _, ipNet := range nets {
+		if ipNet.Ipv4 != ipv4 || !ipNet.Enabled {
+			continue
+		}
+
+		ranges, resp, err := s.ipRanges(ctx, networkID, ipNet.ID)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for i := range ranges {
+			free, resp, err := s.freeAddresses(ctx, networkID, ipNet.ID, &ranges[i], 1)
+			if err != nil {
+				return nil, resp, err
+			}
+
+			if len(free) == 0 {
+				continue
+			}
+
+			assign := &AssignIPAddress{
+				Address:            free[0],
+				IPNetID:            ipNet.ID,
+				IPRangeID:          ranges[i].ID,
+				IPVersion:          ipVersion,
+				NetworkInterfaceID: networkInterfaceID,
+			}
+
+			return s.client.IPAddresses.AssignVS(ctx, vmID, assign)
+		}
+	}
+
+	return nil, resp, fmt.Errorf("Network [%d] has no free IPv%d addresses", networkID, ipVersion)
+}
+
+// findRange return IPNet and IPRange of the Network containing address
+func (s *IPAMServiceOp) findRange(ctx context.Context, networkID int, address string) (*IPNet, *IPRange, *Response, error) {
+	nets, resp, err := s.ipNets(ctx, networkID)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	ip := net.ParseIP(address)
+	for i := range nets {
+		cidr, err := nets[i].CIDR()
+		if err != nil || !cidr.Contains(ip) {
+			continue
+		}
+
+		ranges, resp, err := s.ipRanges(ctx, networkID, nets[i].ID)
+		if err != nil {
+			return nil, nil, resp, err
+		}
+
+		for j := range ranges {
+			if ranges[j].Contains(address) {
+				return &nets[i], &ranges[j], resp, nil
+			}
+		}
+	}
+
+	return nil, nil, resp, fmt.Errorf("address %s does not belong to any IPRange of the Network [%d]", address, networkID)
+}
+
+// interfaceNetwork return Network of the NetworkInterface, NetworkJoin of the
+// interface belongs to the Hypervisor or to the HypervisorGroup of the
+// VirtualMachine
+func (s *IPAMServiceOp) interfaceNetwork(ctx context.Context, vmID int, networkInterfaceID int) (int, *Response, error) {
+	ni, resp, err := s.client.NetworkInterfaces.Get(ctx, vmID, networkInterfaceID)
+	if err != nil {
+		return 0, resp, err
+	}
+
+	vm, resp, err := s.client.VirtualMachines.Get(ctx, vmID)
+	if err != nil {
+		return 0, resp, err
+	}
+
+	hv, resp, err := s.client.Hypervisors.Get(ctx, vm.HypervisorID)
+	if err != nil {
+		return 0, resp, err
+	}
+
+	targets := []JoinTarget{HypervisorJoinTarget(hv.ID)}
+	if hv.HypervisorGroupID > 0 {
+		targets = append(targets, HypervisorGroupJoinTarget(hv.HypervisorGroupID))
+	}
+
+	for _, target := range targets {
+		target := target
+
+		var joins []NetworkJoin
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.client.NetworkJoins.ListByTarget(ctx, target, opt)
+			joins = append(joins, lst...)
+			return len(lst), resp, err
+		})
+		if err != nil {
+			return 0, resp, err
+		}
+
+		for _, join := range joins {
+			if join.ID == ni.NetworkJoinID {
+				return join.NetworkID, resp, nil
+			}
+		}
+	}
+
+	return 0, resp, fmt.Errorf("NetworkJoin [%d] of the NetworkInterface [%d] not found", ni.NetworkJoinID, networkInterfaceID)
+}
+
+// ipNets return all IPNets of the Network
+func (s *IPAMServiceOp) ipNets(ctx context.Context, networkID int) ([]IPNet, *Response, error) {
+	var res []IPNet
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.IPNets.List(ctx, networkID, opt)
+		res = append(res, lst...)
+		return len(lst), resp, err
+	})
+
+	return res, resp, err
+}
+
+// ipRanges return all IPRanges of the IPNet
+func (s *IPAMServiceOp) ipRanges(ctx context.Context, networkID int, ipNetID int) ([]IPRange, *Response, error) {
+	var res []IPRange
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.IPRanges.List(ctx, networkID, ipNetID, opt)
+		res = append(res, lst...)
+		return len(lst), resp, err
+	})
+
+	return res, resp, err
+}
+
+func containsAddress(lst []string, address string) bool {
+	ip := net.ParseIP(address)
+	for _, v := range lst {
+		if net.ParseIP(v).Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,194 @@
+package onappgo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeIPRangeAddresses(t *testing.T) {
+	r := &IPRange{StartAddress: "10.0.0.254", EndAddress: "10.0.1.2", DefaultGateway: "10.0.0.255"}
+
+	free, err := FreeIPRangeAddresses(r, []string{"10.0.1.0"}, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.254", "10.0.1.1", "10.0.1.2"}, free)
+
+	free, err = FreeIPRangeAddresses(r, nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.254"}, free)
+
+	r6 := &IPRange{StartAddress: "2001:db8::", EndAddress: "2001:db8::ffff:ffff:ffff:ffff"}
+	free, err = FreeIPRangeAddresses(r6, []string{"2001:db8::0"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2001:db8::1", "2001:db8::2"}, free)
+
+	last := &IPRange{StartAddress: "255.255.255.254", EndAddress: "255.255.255.255"}
+	free, err = FreeIPRangeAddresses(last, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"255.255.255.254", "255.255.255.255"}, free)
+
+	_, err = FreeIPRangeAddresses(&IPRange{StartAddress: "10.0.0.2", EndAddress: "10.0.0.1"}, nil, 0)
+	require.Error(t, err)
+
+	_, err = FreeIPRangeAddresses(&IPRange{StartAddress: "10.0.0.1", EndAddress: "2001:db8::1"}, nil, 0)
+	require.Error(t, err)
+}
+
+func TestIPRange_Contains(t *testing.T) {
+	r := &IPRange{StartAddress: "10.0.0.10", EndAddress: "10.0.0.20"}
+
+	require.True(t, r.Contains("10.0.0.10"))
+	require.True(t, r.Contains("10.0.0.20"))
+	require.False(t, r.Contains("10.0.0.21"))
+	require.False(t, r.Contains("::ffff:10.0.0.9"))
+	require.False(t, r.Contains("2001:db8::1"))
+	require.False(t, r.Contains("wrong"))
+}
+
+func TestOverlappingIPNets(t *testing.T) {
+	nets := []IPNet{
+		{ID: 1, NetworkAddress: "10.0.0.0", NetworkMask: 16},
+		{ID: 2, NetworkAddress: "10.0.5.0", NetworkMask: 24},
+		{ID: 3, NetworkAddress: "10.1.0.0", NetworkMask: 16},
+		{ID: 4, NetworkAddress: "2001:db8::", NetworkMask: 32},
+		{ID: 5, NetworkAddress: "2001:db8:1::", NetworkMask: 48},
+	}
+
+	res, err := OverlappingIPNets(nets)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, []int{1, 2}, []int{res[0].IPNet.ID, res[0].Overlap.ID})
+	require.Equal(t, []int{4, 5}, []int{res[1].IPNet.ID, res[1].Overlap.ID})
+
+	_, err = OverlappingIPNets([]IPNet{{ID: 1, NetworkAddress: "10.0.0.0", NetworkMask: 33}})
+	require.Error(t, err)
+}
+
+func TestCountFreeIPRangeAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *IPRange
+		used []string
+		want string
+	}{
+		{"small", &IPRange{StartAddress: "10.0.0.254", EndAddress: "10.0.1.2", DefaultGateway: "10.0.0.255"}, []string{"10.0.1.0"}, "3"},
+		{"duplicated and outside", &IPRange{StartAddress: "10.0.0.1", EndAddress: "10.0.0.10", DefaultGateway: "10.0.0.1"}, []string{"10.0.0.1", "10.0.0.2", "10.0.0.2", "10.0.1.2", "wrong"}, "8"},
+		{"ipv4 /8", &IPRange{StartAddress: "10.0.0.0", EndAddress: "10.255.255.255", DefaultGateway: "10.0.0.1"}, []string{"10.0.0.2"}, "16777214"},
+		{"ipv6 /64", &IPRange{StartAddress: "2001:db8::", EndAddress: "2001:db8::ffff:ffff:ffff:ffff"}, []string{"2001:db8::0"}, "18446744073709551615"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := CountFreeIPRangeAddresses(tt.r, tt.used)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, count.String())
+		})
+	}
+
+	r := &IPRange{StartAddress: "10.0.0.254", EndAddress: "10.0.1.2", DefaultGateway: "10.0.0.255"}
+	free, err := FreeIPRangeAddresses(r, []string{"10.0.1.0"}, 0)
+	require.NoError(t, err)
+	count, err := CountFreeIPRangeAddresses(r, []string{"10.0.1.0"})
+	require.NoError(t, err)
+	require.EqualValues(t, len(free), count.Int64())
+
+	_, err = CountFreeIPRangeAddresses(&IPRange{StartAddress: "10.0.0.2", EndAddress: "10.0.0.1"}, nil)
+	require.Error(t, err)
+}
+
+func TestIPAM_Overlaps(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the last IPNet on the second page overlaps the previous one
+	var items []interface{}
+	for i := 1; i < 150; i++ {
+		items = append(items, IPNet{ID: i, NetworkAddress: fmt.Sprintf("10.%d.0.0", i), NetworkMask: 24, Ipv4: true})
+	}
+	items = append(items, IPNet{ID: 150, NetworkAddress: "10.149.0.0", NetworkMask: 16, Ipv4: true})
+
+	mux.HandleFunc("/settings/networks/1/ip_nets.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "ip_net", items)
+	})
+
+	overlaps, _, err := client.IPAM.Overlaps(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	require.Equal(t, 149, overlaps[0].IPNet.ID)
+	require.Equal(t, 150, overlaps[0].Overlap.ID)
+}
+
+func TestIPAM_FreeAddresses(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2/ip_ranges/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ip_range":{"id":3,"start_address":"10.0.0.0","end_address":"10.0.0.255","default_gateway":"10.0.0.1"}}`)
+	})
+
+	// 10.0.0.2 - 10.0.0.151 are used, the second page included
+	var used []interface{}
+	for i := 2; i < 152; i++ {
+		used = append(used, IPAddress{ID: i, Address: fmt.Sprintf("10.0.0.%d", i)})
+	}
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2/ip_ranges/3/ip_addresses.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeTestPage(t, w, r, "ip_address", used)
+	})
+
+	addresses, _, err := client.IPAM.UsedAddresses(ctx, 1, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, addresses, 150)
+
+	free, _, err := client.IPAM.FreeAddresses(ctx, 1, 2, 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0", "10.0.0.152"}, free)
+}
+
+func TestIPAM_Reserve(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/networks/1/ip_nets.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "ip_net", []interface{}{IPNet{ID: 2, NetworkAddress: "10.0.0.0", NetworkMask: 16, Ipv4: true}})
+	})
+
+	// the range of the address is on the second page
+	var ranges []interface{}
+	for i := 1; i <= 150; i++ {
+		ranges = append(ranges, IPRange{ID: i, StartAddress: fmt.Sprintf("10.0.%d.0", i), EndAddress: fmt.Sprintf("10.0.%d.255", i)})
+	}
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2/ip_ranges.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "ip_range", ranges)
+	})
+
+	mux.HandleFunc("/settings/networks/1/ip_nets/2/ip_ranges/150/ip_addresses.json", func(w http.ResponseWriter, r *http.Request) {
+		var used []interface{}
+		for i := 0; i < 150; i++ {
+			used = append(used, IPAddress{ID: i + 1, Address: fmt.Sprintf("10.0.150.%d", i)})
+		}
+		writeTestPage(t, w, r, "ip_address", used)
+	})
+
+	assigned := 0
+	mux.HandleFunc("/settings/networks/1/ip_addresses/assign.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		assigned++
+		fmt.Fprint(w, `{"ip_address":{"id":500,"address":"10.0.150.200"}}`)
+	})
+
+	// used address on the second page of the IPRange addresses
+	_, _, err := client.IPAM.Reserve(ctx, 1, 5, "10.0.150.120")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already used")
+
+	ip, _, err := client.IPAM.Reserve(ctx, 1, 5, "10.0.150.200")
+	require.NoError(t, err)
+	require.Equal(t, 500, ip.ID)
+	require.Equal(t, 1, assigned)
+}
@@ -20,6 +20,7 @@ type IPRangesService interface {
 	Create(context.Context, int, int, *IPRangeCreateRequest) (*IPRange, *Response, error)
 	Delete(context.Context, int, int, int, interface{}) (*Response, error)
 	Edit(context.Context, int, int, int, *IPRangeCreateRequest) (*Response, error)
+	Validate(context.Context, int, int, *IPRangeCreateRequest) (*Response, error)
 }
 
 // IPRangesServiceOp handles communication with the IPRange related methods of the
@@ -0,0 +1,160 @@
+package onappgo
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/digitalocean/godo"
+)
+
+// Types of the objects DataStores, Networks and BackupServers are joined to
+const (
+	JoinTargetHypervisor      = "Hypervisor"
+	JoinTargetHypervisorGroup = "HypervisorGroup"
+)
+
+// JoinTarget - Hypervisor or HypervisorGroup (compute zone) of the
+// DataStoreJoin, NetworkJoin or BackupServerJoin
+type JoinTarget struct {
+	Type string
+	ID   int
+}
+
+// HypervisorJoinTarget returns JoinTarget of the Hypervisor
+func HypervisorJoinTarget(id int) JoinTarget {
+	return JoinTarget{Type: JoinTargetHypervisor, ID: id}
+}
+
+// HypervisorGroupJoinTarget returns JoinTarget of the HypervisorGroup
+func HypervisorGroupJoinTarget(id int) JoinTarget {
+	return JoinTarget{Type: JoinTargetHypervisorGroup, ID: id}
+}
+
+func (t JoinTarget) String() string {
+	return fmt.Sprintf("%s [%d]", t.Type, t.ID)
+}
+
+// Validate check type and ID of the target
+func (t JoinTarget) Validate() error {
+	if t.Type != JoinTargetHypervisor && t.Type != JoinTargetHypervisorGroup {
+		return godo.NewArgError("JoinTarget Type", fmt.Sprintf("must be %s or %s, got %q", JoinTargetHypervisor, JoinTargetHypervisorGroup, t.Type))
+	}
+
+	if t.ID < 1 {
+		return godo.NewArgError("JoinTarget ID", "cannot be less than 1")
+	}
+
+	return nil
+}
+
+// JoinSyncOptions -
+type JoinSyncOptions struct {
+	// Only compute the diff, joins are not created or deleted
+	DryRun bool
+}
+
+// JoinSyncReport - diff between joins of the target and the desired set by
+// IDs of joined DataStores, Networks or BackupServers. Joins are deleted
+// before new ones are created, so NetworkJoin with changed interface is
+// listed both in Removed and Added.
+type JoinSyncReport struct {
+	Target JoinTarget
+	DryRun bool
+
+	Added     []int
+	Removed   []int
+	Unchanged []int
+}
+
+// Changed check if joins of the target differ from the desired set
+func (r *JoinSyncReport) Changed() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+func (r *JoinSyncReport) String() string {
+	prefix := ""
+	if r.DryRun {
+		prefix = "dry run: "
+	}
+
+	return fmt.Sprintf("%s%s added %v, removed %v, unchanged %v", prefix, r.Target, r.Added, r.Removed, r.Unchanged)
+}
+
+// joinItem - existing (joinID > 0) or desired join of the resource
+type joinItem struct {
+	joinID     int
+	resourceID int
+	iface      string
+}
+
+// diffJoins return joins to delete and to create to turn current joins into
+// desired ones, duplicated joins of the same resource are deleted
+func diffJoins(target JoinTarget, current []joinItem, desired []joinItem) (remove []joinItem, add []joinItem, report *JoinSyncReport) {
+	report = &JoinSyncReport{Target: target}
+
+	want := make(map[int]joinItem, len(desired))
+	for _, v := range desired {
+		want[v.resourceID] = v
+	}
+
+	kept := make(map[int]bool, len(current))
+	for _, v := range current {
+		w, ok := want[v.resourceID]
+		if ok && !kept[v.resourceID] && w.iface == v.iface {
+			kept[v.resourceID] = true
+			report.Unchanged = append(report.Unchanged, v.resourceID)
+			continue
+		}
+
+		remove = append(remove, v)
+		report.Removed = append(report.Removed, v.resourceID)
+	}
+
+	for _, v := range desired {
+		if kept[v.resourceID] {
+			continue
+		}
+		kept[v.resourceID] = true
+
+		add = append(add, v)
+		report.Added = append(report.Added, v.resourceID)
+	}
+
+	sort.Ints(report.Added)
+	sort.Ints(report.Removed)
+	sort.Ints(report.Unchanged)
+
+	return remove, add, report
+}
+
+// syncJoins delete and create joins by the diff, report is returned also on
+// error with the changes made so far
+func syncJoins(target JoinTarget, current []joinItem, desired []joinItem, opts *JoinSyncOptions, remove func(joinItem) (*Response, error), add func(joinItem) (*Response, error)) (*JoinSyncReport, *Response, error) {
+	toRemove, toAdd, diff := diffJoins(target, current, desired)
+	if opts != nil && opts.DryRun {
+		diff.DryRun = true
+		return diff, nil, nil
+	}
+
+	report := &JoinSyncReport{Target: target, Unchanged: diff.Unchanged}
+
+	var resp *Response
+	var err error
+	for _, v := range toRemove {
+		if resp, err = remove(v); err != nil {
+			return report, resp, fmt.Errorf("%s: join [%d] of [%d] is not deleted: %s", target, v.joinID, v.resourceID, err)
+		}
+		report.Removed = append(report.Removed, v.resourceID)
+	}
+
+	for _, v := range toAdd {
+		if resp, err = add(v); err != nil {
+			return report, resp, fmt.Errorf("%s: [%d] is not joined: %s", target, v.resourceID, err)
+		}
+		report.Added = append(report.Added, v.resourceID)
+	}
+	sort.Ints(report.Added)
+	sort.Ints(report.Removed)
+
+	return report, resp, nil
+}
@@ -0,0 +1,135 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinTarget_Validate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	require.NoError(t, HypervisorJoinTarget(1).Validate())
+	require.NoError(t, HypervisorGroupJoinTarget(2).Validate())
+	require.Error(t, JoinTarget{Type: "hypervisor_zone", ID: 2}.Validate())
+	require.Error(t, HypervisorGroupJoinTarget(0).Validate())
+
+	_, _, err := client.DataStoreJoins.ListByTarget(ctx, JoinTarget{Type: "Hypervisors", ID: 1}, nil)
+	require.Error(t, err)
+}
+
+func TestDataStoreJoins_Sync(t *testing.T) {
+	setup()
+	defer teardown()
+
+	joins := map[int]DataStoreJoin{
+		1: {ID: 1, DataStoreID: 10, TargetJoinType: JoinTargetHypervisorGroup, TargetJoinID: 5},
+		2: {ID: 2, DataStoreID: 11, TargetJoinType: JoinTargetHypervisorGroup, TargetJoinID: 5},
+	}
+	nextID := 3
+
+	mux.HandleFunc("/settings/hypervisor_zones/5/data_store_joins.json", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			var out []map[string]DataStoreJoin
+			for i := 1; i < nextID; i++ {
+				if v, ok := joins[i]; ok {
+					out = append(out, map[string]DataStoreJoin{"data_store_join": v})
+				}
+			}
+			json.NewEncoder(w).Encode(out)
+		case http.MethodPost:
+			var req dataStoreJoinCreateRequestRoot
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			join := DataStoreJoin{ID: nextID, DataStoreID: req.DataStoreID}
+			joins[nextID] = join
+			nextID++
+			json.NewEncoder(w).Encode(&dataStoreJoinRoot{DataStoreJoin: &join})
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	mux.HandleFunc("/settings/hypervisor_zones/5/data_store_joins/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/settings/hypervisor_zones/5/data_store_joins/"), "%d.json", &id)
+		delete(joins, id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	target := HypervisorGroupJoinTarget(5)
+
+	report, _, err := client.DataStoreJoins.Sync(ctx, target, []int{11, 12}, &JoinSyncOptions{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, []int{12}, report.Added)
+	require.Equal(t, []int{10}, report.Removed)
+	require.Len(t, joins, 2)
+
+	report, _, err = client.DataStoreJoins.Sync(ctx, target, []int{11, 12}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int{12}, report.Added)
+	require.Equal(t, []int{10}, report.Removed)
+	require.Equal(t, []int{11}, report.Unchanged)
+	require.Len(t, joins, 2)
+
+	report, _, err = client.DataStoreJoins.Sync(ctx, target, []int{12, 11}, nil)
+	require.NoError(t, err)
+	require.False(t, report.Changed())
+	require.Equal(t, []int{11, 12}, report.Unchanged)
+}
+
+func TestDiffJoins_NetworkInterface(t *testing.T) {
+	current := []joinItem{
+		{joinID: 1, resourceID: 10, iface: "eth0"},
+		{joinID: 2, resourceID: 11, iface: "eth1"},
+		{joinID: 3, resourceID: 11, iface: "eth1"},
+	}
+	desired := []joinItem{
+		{resourceID: 10, iface: "eth2"},
+		{resourceID: 11, iface: "eth1"},
+	}
+
+	remove, add, report := diffJoins(HypervisorJoinTarget(1), current, desired)
+	require.Equal(t, []joinItem{current[0], current[2]}, remove)
+	require.Equal(t, []joinItem{desired[0]}, add)
+	require.Equal(t, []int{10}, report.Added)
+	require.Equal(t, []int{10, 11}, report.Removed)
+	require.Equal(t, []int{11}, report.Unchanged)
+}
+
+func TestBackupServerJoins_SyncAllPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var items []interface{}
+	var desired []int
+	for i := 1; i <= 150; i++ {
+		items = append(items, BackupServerJoin{ID: i, BackupServerID: 100 + i})
+		if i != 120 {
+			desired = append(desired, 100+i)
+		}
+	}
+
+	pages := 0
+	mux.HandleFunc("/settings/hypervisors/3/backup_server_joins.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		pages++
+		writeTestPage(t, w, r, "backup_server_join", items)
+	})
+
+	report, _, err := client.BackupServerJoins.Sync(ctx, HypervisorJoinTarget(3), desired, &JoinSyncOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, pages)
+	require.Empty(t, report.Added)
+	require.Equal(t, []int{220}, report.Removed)
+	require.Len(t, report.Unchanged, 149)
+}
@@ -1,5 +1,8 @@
 package onappgo
 
+// allPagesPerPage is a page size used to walk through all pages of a list
+const allPagesPerPage = 100
+
 // Links manages links that are returned along with a List
 type Links struct {
 	PerPage  int
@@ -17,3 +20,34 @@ func (l *Links) CurrentPage() (int, error) {
 func (l *Links) IsLastPage() bool {
 	return l.CurPage == l.NumPages
 }
+
+// hasNextPage reports whether one more page must be requested after a page
+// with count items was received for opt
+func hasNextPage(resp *Response, opt *ListOptions, count int) bool {
+	if resp == nil || resp.Links == nil || count == 0 {
+		return false
+	}
+
+	if opt != nil && opt.PerPage > 0 && count < opt.PerPage {
+		return false
+	}
+
+	return resp.Links.CurPage < resp.Links.NumPages
+}
+
+// listAllPages call list for every page until the last one, list returns
+// number of items received for the page
+func listAllPages(list func(*ListOptions) (int, *Response, error)) (*Response, error) {
+	opt := &ListOptions{
+		Page:    1,
+		PerPage: allPagesPerPage,
+	}
+
+	for {
+		count, resp, err := list(opt)
+		if err != nil || !hasNextPage(resp, opt, count) {
+			return resp, err
+		}
+		opt.Page++
+	}
+}
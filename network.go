@@ -20,6 +20,8 @@ type NetworksService interface {
 	Create(context.Context, *NetworkCreateRequest) (*Network, *Response, error)
 	Delete(context.Context, int, interface{}) (*Response, error)
 	Edit(context.Context, int, *NetworkEditRequest) (*Response, error)
+
+	Topology(context.Context) (*NetworkTopology, *Response, error)
 }
 
 // NetworksServiceOp handles communication with the Networks related methods of the
@@ -20,6 +20,13 @@ type NetworkInterfacesService interface {
 	Create(context.Context, int, *NetworkInterfaceCreateRequest) (*NetworkInterface, *Response, error)
 	Delete(context.Context, int, int, interface{}) (*Response, error)
 	Edit(context.Context, int, int, *NetworkInterfaceEditRequest) (*Response, error)
+
+	Connect(context.Context, int, int) (*Transaction, *Response, error)
+	Disconnect(context.Context, int, int) (*Transaction, *Response, error)
+	SetAdapterType(context.Context, int, int, string) (*Transaction, *Response, error)
+	SetRateLimit(context.Context, int, int, int) (*Transaction, *Response, error)
+	ResetUsage(context.Context, int, int) (*Response, error)
+	AddAndConnect(context.Context, int, *NetworkInterfaceCreateRequest, *TransactionWaitOptions) (*NetworkInterface, *Response, error)
 }
 
 // NetworkInterfacesServiceOp handles communication with the NetworkInterfaces related methods of the
@@ -192,8 +199,11 @@ func (s *NetworkInterfacesServiceOp) Edit(ctx context.Context, vmID int, id int,
 
 	path := fmt.Sprintf(networkInterfacesBasePath, vmID)
 	path = fmt.Sprintf("%s/%d%s", path, id, apiFormat)
+	rootRequest := &networkInterfaceUpdateRoot{
+		NetworkInterface: editRequest,
+	}
 
-	req, err := s.client.NewRequest(ctx, http.MethodPut, path, editRequest)
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,177 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const networkInterfaceResetUsageBasePath string = networkInterfacesBasePath + "/%d/reset_usage"
+
+// Actions of the NetworkInterface transactions
+var (
+	networkInterfaceAttachActions     = []string{"attach_network_interface"}
+	networkInterfaceConnectActions    = []string{"attach_network_interface", "rebuild_network"}
+	networkInterfaceDisconnectActions = []string{"detach_network_interface", "rebuild_network"}
+	networkInterfaceUpdateActions     = []string{"update_network_interface", "rebuild_network"}
+	networkInterfaceRateLimitActions  = []string{"update_rate_limit", "rebuild_network"}
+)
+
+type networkInterfaceConnectRequest struct {
+	Connected bool `json:"connected"`
+}
+
+type networkInterfaceAdapterTypeRequest struct {
+	AdapterType string `json:"adapter_type"`
+}
+
+type networkInterfaceRateLimitRequest struct {
+	// 0 means unlimited port speed, so it's always sent
+	RateLimit int `json:"rate_limit"`
+}
+
+type networkInterfaceUpdateRoot struct {
+	NetworkInterface interface{} `json:"network_interface"`
+}
+
+// Connect NetworkInterface of the VirtualMachine to its Network
+func (s *NetworkInterfacesServiceOp) Connect(ctx context.Context, vmID int, id int) (*Transaction, *Response, error) {
+	return s.update(ctx, vmID, id, &networkInterfaceConnectRequest{Connected: true}, "Connect", networkInterfaceConnectActions)
+}
+
+// Disconnect NetworkInterface of the VirtualMachine from its Network
+func (s *NetworkInterfacesServiceOp) Disconnect(ctx context.Context, vmID int, id int) (*Transaction, *Response, error) {
+	return s.update(ctx, vmID, id, &networkInterfaceConnectRequest{Connected: false}, "Disconnect", networkInterfaceDisconnectActions)
+}
+
+// SetAdapterType change type of the NetworkInterface adapter, e.g. "virtio"
+func (s *NetworkInterfacesServiceOp) SetAdapterType(ctx context.Context, vmID int, id int, adapterType string) (*Transaction, *Response, error) {
+	if adapterType == "" {
+		return nil, nil, godo.NewArgError("adapterType", "cannot be empty")
+	}
+
+	return s.update(ctx, vmID, id, &networkInterfaceAdapterTypeRequest{AdapterType: adapterType}, "SetAdapterType", networkInterfaceUpdateActions)
+}
+
+// SetRateLimit change port speed of the NetworkInterface in Mbps, 0 means unlimited
+func (s *NetworkInterfacesServiceOp) SetRateLimit(ctx context.Context, vmID int, id int, rateLimit int) (*Transaction, *Response, error) {
+	if rateLimit < 0 {
+		return nil, nil, godo.NewArgError("rateLimit", "cannot be less than 0")
+	}
+
+	return s.update(ctx, vmID, id, &networkInterfaceRateLimitRequest{RateLimit: rateLimit}, "SetRateLimit", networkInterfaceRateLimitActions)
+}
+
+// ResetUsage reset traffic usage of the NetworkInterface, it's done without
+// a transaction
+func (s *NetworkInterfacesServiceOp) ResetUsage(ctx context.Context, vmID int, id int) (*Response, error) {
+	if vmID < 1 || id < 1 {
+		return nil, godo.NewArgError("vmID || id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(networkInterfaceResetUsageBasePath, vmID, id) + apiFormat
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("NetworkInterface [ResetUsage]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+func (s *NetworkInterfacesServiceOp) update(ctx context.Context, vmID int, id int, body interface{}, name string, actions []string) (*Transaction, *Response, error) {
+	if vmID < 1 || id < 1 {
+		return nil, nil, godo.NewArgError("vmID || id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf(networkInterfacesBasePath, vmID)
+	path = fmt.Sprintf("%s/%d%s", path, id, apiFormat)
+	rootRequest := &networkInterfaceUpdateRoot{
+		NetworkInterface: body,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("NetworkInterface [%s]  req: %v\n", name, req)
+
+	start := time.Now()
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return networkInterfaceTransaction(ctx, s.client, id, serverTime(resp, start), actions)
+}
+
+// AddAndConnect add NetworkInterface to the running VirtualMachine and wait
+// until transaction of adding is finished and the interface is Connected
+func (s *NetworkInterfacesServiceOp) AddAndConnect(ctx context.Context, vmID int, createRequest *NetworkInterfaceCreateRequest, opts *TransactionWaitOptions) (*NetworkInterface, *Response, error) {
+	if opts == nil {
+		opts = &TransactionWaitOptions{}
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultTransactionWaitInterval
+	}
+
+	vm, resp, err := s.client.VirtualMachines.Get(ctx, vmID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if !vm.Booted {
+		return nil, resp, fmt.Errorf("VirtualMachine [%d] is not running", vmID)
+	}
+
+	start := time.Now()
+	ni, resp, err := s.Create(ctx, vmID, createRequest)
+	if err != nil {
+		return nil, resp, err
+	}
+	since := serverTime(resp, start)
+
+	// transaction could appear later than the interface
+	trx, _, _ := networkInterfaceTransaction(ctx, s.client, ni.ID, since, networkInterfaceAttachActions)
+	if trx != nil {
+		_, resp, err = s.client.Transactions.Wait(ctx, []Transaction{*trx}, opts)
+		if err != nil {
+			return ni, resp, err
+		}
+	}
+
+	for {
+		cur, resp, err := s.Get(ctx, vmID, ni.ID)
+		if err != nil {
+			return ni, resp, err
+		}
+
+		if cur.Connected {
+			return cur, resp, nil
+		}
+
+		if trx == nil {
+			trx, _, _ = networkInterfaceTransaction(ctx, s.client, ni.ID, since, networkInterfaceAttachActions)
+			if trx != nil && trx.Unlucky() {
+				return cur, resp, fmt.Errorf("NetworkInterface [%d] transaction [%d] is %s", ni.ID, trx.ID, trx.Status)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return cur, resp, fmt.Errorf("NetworkInterface [%d] is not connected: %s", ni.ID, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func networkInterfaceTransaction(ctx context.Context, client *Client, id int, since time.Time, actions []string) (*Transaction, *Response, error) {
+	return transactionSince(ctx, client, "NetworkInterface", id, since, actions...)
+}
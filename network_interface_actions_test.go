@@ -0,0 +1,183 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupNetworkInterfaceTransactions serve transactions of the
+// NetworkInterface 5: 11 created now with the action, 5 with the action
+// created long ago and 12 created now with another action
+func setupNetworkInterfaceTransactions(t *testing.T, action string, status string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		json.NewEncoder(w).Encode([]map[string]Transaction{
+			{"transaction": {ID: 12, Action: "update_firewall_rules", AssociatedObjectType: "NetworkInterface", AssociatedObjectID: 5, CreatedAt: now, Status: TransactionPending}},
+			{"transaction": {ID: 11, Action: action, AssociatedObjectType: "NetworkInterface", AssociatedObjectID: 5, CreatedAt: now, Status: status}},
+			{"transaction": {ID: 5, Action: action, AssociatedObjectType: "NetworkInterface", AssociatedObjectID: 5, CreatedAt: "2020-01-01T00:00:00Z", Status: TransactionFailed}},
+		})
+	})
+}
+
+func TestNetworkInterfaces_SetRateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/network_interfaces/5.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		var got map[string]map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, map[string]interface{}{"rate_limit": float64(0)}, got["network_interface"])
+	})
+
+	setupNetworkInterfaceTransactions(t, "update_rate_limit", TransactionPending)
+
+	trx, _, err := client.NetworkInterfaces.SetRateLimit(ctx, 1, 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, 11, trx.ID)
+
+	_, _, err = client.NetworkInterfaces.SetRateLimit(ctx, 1, 5, -1)
+	require.Error(t, err)
+}
+
+func TestNetworkInterfaces_Connect(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		connect bool
+	}{
+		{"connect", "attach_network_interface", true},
+		{"disconnect", "detach_network_interface", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			mux.HandleFunc("/virtual_machines/1/network_interfaces/5.json", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodPut)
+
+				var got map[string]map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				require.Equal(t, map[string]interface{}{"connected": tt.connect}, got["network_interface"])
+			})
+
+			setupNetworkInterfaceTransactions(t, tt.action, TransactionPending)
+
+			var trx *Transaction
+			var err error
+			if tt.connect {
+				trx, _, err = client.NetworkInterfaces.Connect(ctx, 1, 5)
+			} else {
+				trx, _, err = client.NetworkInterfaces.Disconnect(ctx, 1, 5)
+			}
+			require.NoError(t, err)
+			require.Equal(t, 11, trx.ID)
+			require.Equal(t, tt.action, trx.Action)
+		})
+	}
+}
+
+func TestNetworkInterfaces_ConnectNoTransaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/network_interfaces/5.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+	})
+
+	// only the old transaction and the one of another action
+	setupNetworkInterfaceTransactions(t, "detach_network_interface", TransactionPending)
+
+	trx, _, err := client.NetworkInterfaces.Connect(ctx, 1, 5)
+	require.Error(t, err)
+	require.Nil(t, trx)
+}
+
+func TestNetworkInterfaces_SetAdapterType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1/network_interfaces/5.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		var got map[string]map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, map[string]interface{}{"adapter_type": "virtio"}, got["network_interface"])
+	})
+
+	setupNetworkInterfaceTransactions(t, "update_network_interface", TransactionPending)
+
+	trx, _, err := client.NetworkInterfaces.SetAdapterType(ctx, 1, 5, "virtio")
+	require.NoError(t, err)
+	require.Equal(t, 11, trx.ID)
+
+	_, _, err = client.NetworkInterfaces.SetAdapterType(ctx, 1, 5, "")
+	require.Error(t, err)
+
+	_, _, err = client.NetworkInterfaces.SetAdapterType(ctx, 0, 5, "virtio")
+	require.Error(t, err)
+}
+
+func TestNetworkInterfaces_ResetUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	resets := 0
+	mux.HandleFunc("/virtual_machines/1/network_interfaces/5/reset_usage.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		resets++
+	})
+
+	mux.HandleFunc("/transactions.json", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("transactions must not be requested")
+	})
+
+	_, err := client.NetworkInterfaces.ResetUsage(ctx, 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, 1, resets)
+
+	_, err = client.NetworkInterfaces.ResetUsage(ctx, 1, 0)
+	require.Error(t, err)
+	require.Equal(t, 1, resets)
+}
+
+func TestNetworkInterfaces_AddAndConnect(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/virtual_machines/1.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"virtual_machine":{"id":1,"booted":true}}`)
+	})
+
+	mux.HandleFunc("/virtual_machines/1/network_interfaces.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{"network_interface":{"id":5,"virtual_machine_id":1,"connected":false}}`)
+	})
+
+	polls := 0
+	mux.HandleFunc("/virtual_machines/1/network_interfaces/5.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		polls++
+		fmt.Fprintf(w, `{"network_interface":{"id":5,"virtual_machine_id":1,"connected":%t}}`, polls > 1)
+	})
+
+	setupNetworkInterfaceTransactions(t, "attach_network_interface", TransactionComplete)
+	mux.HandleFunc("/transactions/11.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]Transaction{"transaction": {ID: 11, Status: TransactionComplete}})
+	})
+
+	ni, _, err := client.NetworkInterfaces.AddAndConnect(ctx, 1, &NetworkInterfaceCreateRequest{Label: "eth1", NetworkJoinID: 3},
+		&TransactionWaitOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	require.True(t, ni.Connected)
+	require.Equal(t, 2, polls)
+}
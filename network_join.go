@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 
 	"github.com/digitalocean/godo"
 )
 
 var networkJoinPaths = map[string]string{
-	"Hypervisor":      "settings/hypervisors/%d/network_joins",
-	"HypervisorGroup": "settings/hypervisor_zones/%d/network_joins",
+	JoinTargetHypervisor:      "settings/hypervisors/%d/network_joins",
+	JoinTargetHypervisorGroup: "settings/hypervisor_zones/%d/network_joins",
 }
 
 // NetworkJoinsService is an interface for interfacing with the NetworkJoin
@@ -20,6 +21,8 @@ type NetworkJoinsService interface {
 	Get(context.Context, string, int, int) (*NetworkJoin, *Response, error)
 	Create(context.Context, *NetworkJoinCreateRequest) (*NetworkJoin, *Response, error)
 	Delete(context.Context, *NetworkJoinDeleteRequest, interface{}) (*Response, error)
+	ListByTarget(context.Context, JoinTarget, *ListOptions) ([]NetworkJoin, *Response, error)
+	Sync(context.Context, JoinTarget, map[int]string, *JoinSyncOptions) (*JoinSyncReport, *Response, error)
 }
 
 // NetworkJoinsServiceOp -
@@ -192,3 +195,53 @@ func (s *NetworkJoinsServiceOp) Delete(ctx context.Context, deleteRequest *Netwo
 
 	return s.client.Do(ctx, req, nil)
 }
+
+// ListByTarget - List NetworkJoins of the Hypervisor or HypervisorGroup
+func (s *NetworkJoinsServiceOp) ListByTarget(ctx context.Context, target JoinTarget, opt *ListOptions) ([]NetworkJoin, *Response, error) {
+	if err := target.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.List(ctx, &NetworkJoinCreateRequest{TargetJoinType: target.Type, TargetJoinID: target.ID}, opt)
+}
+
+// Sync NetworkJoins of the target with desired Networks mapped to their
+// interfaces, joins with other interface are recreated
+func (s *NetworkJoinsServiceOp) Sync(ctx context.Context, target JoinTarget, networks map[int]string, opts *JoinSyncOptions) (*JoinSyncReport, *Response, error) {
+	var joins []NetworkJoin
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.ListByTarget(ctx, target, opt)
+		joins = append(joins, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	current := make([]joinItem, len(joins))
+	for i, v := range joins {
+		current[i] = joinItem{joinID: v.ID, resourceID: v.NetworkID, iface: v.Interface}
+	}
+
+	desired := make([]joinItem, 0, len(networks))
+	for id, iface := range networks {
+		desired = append(desired, joinItem{resourceID: id, iface: iface})
+	}
+	sort.Slice(desired, func(i, j int) bool { return desired[i].resourceID < desired[j].resourceID })
+
+	remove := func(v joinItem) (*Response, error) {
+		return s.Delete(ctx, &NetworkJoinDeleteRequest{ID: v.joinID, TargetJoinType: target.Type, TargetJoinID: target.ID}, nil)
+	}
+
+	add := func(v joinItem) (*Response, error) {
+		_, resp, err := s.Create(ctx, &NetworkJoinCreateRequest{
+			NetworkID:      v.resourceID,
+			Interface:      v.iface,
+			TargetJoinType: target.Type,
+			TargetJoinID:   target.ID,
+		})
+		return resp, err
+	}
+
+	return syncJoins(target, current, desired, opts, remove, add)
+}
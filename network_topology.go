@@ -0,0 +1,451 @@
+package onappgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of the NetworkTopology nodes
+const (
+	TopologyNetworkGroup     = "NetworkGroup"
+	TopologyNetwork          = "Network"
+	TopologyIPNet            = "IPNet"
+	TopologyIPRange          = "IPRange"
+	TopologyHypervisorGroup  = "HypervisorGroup"
+	TopologyHypervisor       = "Hypervisor"
+	TopologyVirtualMachine   = "VirtualMachine"
+	TopologyNetworkInterface = "NetworkInterface"
+)
+
+// Relations of the NetworkTopology edges
+const (
+	TopologyContains = "contains"
+	TopologyJoined   = "joined"
+	TopologyHosts    = "hosts"
+	TopologyUses     = "uses"
+)
+
+// NetworkTopologyResources - resources the NetworkTopology is built from
+type NetworkTopologyResources struct {
+	NetworkGroups     []NetworkGroup
+	Networks          []Network
+	IPNets            map[int][]IPNet   // by Network ID
+	IPRanges          map[int][]IPRange // by IPNet ID
+	HypervisorGroups  []HypervisorGroup
+	Hypervisors       []Hypervisor
+	NetworkJoins      []NetworkJoin
+	VirtualMachines   []VirtualMachine
+	NetworkInterfaces []NetworkInterface
+}
+
+// TopologyNode - resource in the NetworkTopology
+type TopologyNode struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	ObjectID int    `json:"object_id"`
+	Label    string `json:"label,omitempty"`
+}
+
+// TopologyEdge - relation between resources in the NetworkTopology
+type TopologyEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+	Label    string `json:"label,omitempty"`
+}
+
+// NetworkTopology - graph of the Networks and resources they are joined and
+// used by
+type NetworkTopology struct {
+	nodes map[string]*TopologyNode
+	edges []TopologyEdge
+	in    map[string][]TopologyEdge
+}
+
+// NewNetworkTopology return empty NetworkTopology
+func NewNetworkTopology() *NetworkTopology {
+	return &NetworkTopology{
+		nodes: make(map[string]*TopologyNode),
+		in:    make(map[string][]TopologyEdge),
+	}
+}
+
+// TopologyNodeID return ID of the node of the resource
+func TopologyNodeID(kind string, id int) string {
+	return kind + ":" + strconv.Itoa(id)
+}
+
+// AddNode add resource to the graph, existing node is returned if it is
+// already added
+func (g *NetworkTopology) AddNode(kind string, id int, label string) *TopologyNode {
+	key := TopologyNodeID(kind, id)
+	if node, ok := g.nodes[key]; ok {
+		return node
+	}
+
+	node := &TopologyNode{ID: key, Kind: kind, ObjectID: id, Label: label}
+	g.nodes[key] = node
+
+	return node
+}
+
+// AddEdge add relation between nodes
+func (g *NetworkTopology) AddEdge(from *TopologyNode, to *TopologyNode, relation string, label string) {
+	edge := TopologyEdge{From: from.ID, To: to.ID, Relation: relation, Label: label}
+
+	g.edges = append(g.edges, edge)
+	g.in[to.ID] = append(g.in[to.ID], edge)
+}
+
+// Node return node of the resource
+func (g *NetworkTopology) Node(kind string, id int) (*TopologyNode, bool) {
+	node, ok := g.nodes[TopologyNodeID(kind, id)]
+	return node, ok
+}
+
+// Nodes return all nodes sorted by kind and object ID
+func (g *NetworkTopology) Nodes() []TopologyNode {
+	res := make([]TopologyNode, 0, len(g.nodes))
+	for _, v := range g.nodes {
+		res = append(res, *v)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return topologyNodeLess(&res[i], &res[j]) })
+
+	return res
+}
+
+// Edges return all edges in the order they were added
+func (g *NetworkTopology) Edges() []TopologyEdge {
+	res := make([]TopologyEdge, len(g.edges))
+	copy(res, g.edges)
+
+	return res
+}
+
+// BuildNetworkTopology build graph from the resources
+func BuildNetworkTopology(res *NetworkTopologyResources) *NetworkTopology {
+	g := NewNetworkTopology()
+
+	for _, v := range res.NetworkGroups {
+		g.AddNode(TopologyNetworkGroup, v.ID, v.Label)
+	}
+
+	for _, v := range res.Networks {
+		network := g.AddNode(TopologyNetwork, v.ID, v.Label)
+		if v.NetworkGroupID > 0 {
+			g.AddEdge(g.AddNode(TopologyNetworkGroup, v.NetworkGroupID, ""), network, TopologyContains, "")
+		}
+
+		for _, n := range res.IPNets[v.ID] {
+			ipNet := g.AddNode(TopologyIPNet, n.ID, fmt.Sprintf("%s/%d", n.NetworkAddress, n.NetworkMask))
+			g.AddEdge(network, ipNet, TopologyContains, "")
+
+			for _, r := range res.IPRanges[n.ID] {
+				ipRange := g.AddNode(TopologyIPRange, r.ID, r.StartAddress+"-"+r.EndAddress)
+				g.AddEdge(ipNet, ipRange, TopologyContains, "")
+			}
+		}
+	}
+
+	for _, v := range res.HypervisorGroups {
+		g.AddNode(TopologyHypervisorGroup, v.ID, v.Label)
+	}
+
+	for _, v := range res.Hypervisors {
+		hv := g.AddNode(TopologyHypervisor, v.ID, v.Label)
+		if v.HypervisorGroupID > 0 {
+			g.AddEdge(g.AddNode(TopologyHypervisorGroup, v.HypervisorGroupID, ""), hv, TopologyContains, "")
+		}
+	}
+
+	joins := make(map[int]NetworkJoin, len(res.NetworkJoins))
+	for _, v := range res.NetworkJoins {
+		joins[v.ID] = v
+
+		var kind string
+		switch v.TargetJoinType {
+		case JoinTargetHypervisor:
+			kind = TopologyHypervisor
+		case JoinTargetHypervisorGroup:
+			kind = TopologyHypervisorGroup
+		default:
+			continue
+		}
+
+		network := g.AddNode(TopologyNetwork, v.NetworkID, "")
+		target := g.AddNode(kind, v.TargetJoinID, "")
+		g.AddEdge(network, target, TopologyJoined, v.Interface)
+	}
+
+	for _, v := range res.VirtualMachines {
+		vm := g.AddNode(TopologyVirtualMachine, v.ID, v.Label)
+		if v.HypervisorID > 0 {
+			g.AddEdge(g.AddNode(TopologyHypervisor, v.HypervisorID, ""), vm, TopologyHosts, "")
+		}
+	}
+
+	for _, v := range res.NetworkInterfaces {
+		ni := g.AddNode(TopologyNetworkInterface, v.ID, v.Label)
+		g.AddEdge(g.AddNode(TopologyVirtualMachine, v.VirtualMachineID, ""), ni, TopologyContains, "")
+
+		if join, ok := joins[v.NetworkJoinID]; ok {
+			g.AddEdge(ni, g.AddNode(TopologyNetwork, join.NetworkID, ""), TopologyUses, join.Interface)
+		}
+	}
+
+	return g
+}
+
+// HypervisorNetworks return Networks joined to the Hypervisor directly or
+// through its HypervisorGroup
+func (g *NetworkTopology) HypervisorNetworks(hypervisorID int) []TopologyNode {
+	targets := []string{TopologyNodeID(TopologyHypervisor, hypervisorID)}
+	for _, e := range g.in[targets[0]] {
+		if e.Relation == TopologyContains && strings.HasPrefix(e.From, TopologyHypervisorGroup+":") {
+			targets = append(targets, e.From)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		for _, e := range g.in[target] {
+			if e.Relation == TopologyJoined {
+				seen[e.From] = true
+			}
+		}
+	}
+
+	return g.collect(seen)
+}
+
+// VirtualMachineNetworks return Networks reachable by the Hypervisor of the
+// VirtualMachine
+func (g *NetworkTopology) VirtualMachineNetworks(vmID int) []TopologyNode {
+	for _, e := range g.in[TopologyNodeID(TopologyVirtualMachine, vmID)] {
+		if e.Relation == TopologyHosts {
+			return g.HypervisorNetworks(g.nodes[e.From].ObjectID)
+		}
+	}
+
+	return nil
+}
+
+// NetworkVirtualMachines return VirtualMachines with NetworkInterfaces
+// connected to the Network
+func (g *NetworkTopology) NetworkVirtualMachines(networkID int) []TopologyNode {
+	seen := make(map[string]bool)
+	for _, e := range g.in[TopologyNodeID(TopologyNetwork, networkID)] {
+		if e.Relation != TopologyUses {
+			continue
+		}
+
+		for _, owner := range g.in[e.From] {
+			if strings.HasPrefix(owner.From, TopologyVirtualMachine+":") {
+				seen[owner.From] = true
+			}
+		}
+	}
+
+	return g.collect(seen)
+}
+
+func (g *NetworkTopology) collect(ids map[string]bool) []TopologyNode {
+	res := make([]TopologyNode, 0, len(ids))
+	for id := range ids {
+		res = append(res, *g.nodes[id])
+	}
+
+	sort.Slice(res, func(i, j int) bool { return topologyNodeLess(&res[i], &res[j]) })
+
+	return res
+}
+
+func topologyNodeLess(a *TopologyNode, b *TopologyNode) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+
+	return a.ObjectID < b.ObjectID
+}
+
+type networkTopologyJSON struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// MarshalJSON export graph as lists of nodes and edges
+func (g *NetworkTopology) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&networkTopologyJSON{
+		Nodes: g.Nodes(),
+		Edges: g.Edges(),
+	})
+}
+
+// WriteDOT export graph in the Graphviz DOT format
+func (g *NetworkTopology) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph onapp_network_topology {\n")
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, v := range g.Nodes() {
+		label := v.ID
+		if v.Label != "" {
+			label = v.ID + "\n" + v.Label
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", strconv.Quote(v.ID), strconv.Quote(label), topologyShape(v.Kind))
+	}
+
+	for _, e := range g.edges {
+		label := e.Relation
+		if e.Label != "" {
+			label = e.Relation + " " + e.Label
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(label))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func topologyShape(kind string) string {
+	switch kind {
+	case TopologyNetwork, TopologyNetworkGroup:
+		return "ellipse"
+	case TopologyIPNet, TopologyIPRange:
+		return "note"
+	case TopologyVirtualMachine, TopologyNetworkInterface:
+		return "component"
+	}
+
+	return "box"
+}
+
+// Topology fetch Networks with related resources and build their graph
+func (s *NetworksServiceOp) Topology(ctx context.Context) (*NetworkTopology, *Response, error) {
+	res := &NetworkTopologyResources{
+		IPNets:   make(map[int][]IPNet),
+		IPRanges: make(map[int][]IPRange),
+	}
+
+	var resp *Response
+	var err error
+
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.NetworkGroups.List(ctx, opt)
+		res.NetworkGroups = append(res.NetworkGroups, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.List(ctx, opt)
+		res.Networks = append(res.Networks, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, network := range res.Networks {
+		network := network
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.client.IPNets.List(ctx, network.ID, opt)
+			res.IPNets[network.ID] = append(res.IPNets[network.ID], lst...)
+			return len(lst), resp, err
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, ipNet := range res.IPNets[network.ID] {
+			ipNet := ipNet
+			resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+				lst, resp, err := s.client.IPRanges.List(ctx, network.ID, ipNet.ID, opt)
+				res.IPRanges[ipNet.ID] = append(res.IPRanges[ipNet.ID], lst...)
+				return len(lst), resp, err
+			})
+			if err != nil {
+				return nil, resp, err
+			}
+		}
+	}
+
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.HypervisorGroups.List(ctx, opt)
+		res.HypervisorGroups = append(res.HypervisorGroups, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		lst, resp, err := s.client.Hypervisors.List(ctx, opt)
+		res.Hypervisors = append(res.Hypervisors, lst...)
+		return len(lst), resp, err
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var targets []JoinTarget
+	for _, v := range res.Hypervisors {
+		targets = append(targets, HypervisorJoinTarget(v.ID))
+	}
+	for _, v := range res.HypervisorGroups {
+		targets = append(targets, HypervisorGroupJoinTarget(v.ID))
+	}
+
+	for _, target := range targets {
+		target := target
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			joins, resp, err := s.client.NetworkJoins.ListByTarget(ctx, target, opt)
+			for _, join := range joins {
+				// target is not always filled in the response
+				join.TargetJoinType = target.Type
+				join.TargetJoinID = target.ID
+				res.NetworkJoins = append(res.NetworkJoins, join)
+			}
+			return len(joins), resp, err
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	res.VirtualMachines, resp, err = s.client.VirtualMachines.Select(ctx, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, vm := range res.VirtualMachines {
+		vm := vm
+		resp, err = listAllPages(func(opt *ListOptions) (int, *Response, error) {
+			lst, resp, err := s.client.NetworkInterfaces.List(ctx, vm.ID, opt)
+			for _, ni := range lst {
+				if ni.VirtualMachineID == 0 {
+					ni.VirtualMachineID = vm.ID
+				}
+				res.NetworkInterfaces = append(res.NetworkInterfaces, ni)
+			}
+			return len(lst), resp, err
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	return BuildNetworkTopology(res), resp, nil
+}
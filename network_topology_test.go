@@ -0,0 +1,154 @@
+package onappgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testNetworkTopology() *NetworkTopology {
+	return BuildNetworkTopology(&NetworkTopologyResources{
+		NetworkGroups: []NetworkGroup{{ID: 1, Label: "zone"}},
+		Networks: []Network{
+			{ID: 10, Label: "public", NetworkGroupID: 1},
+			{ID: 11, Label: "private", NetworkGroupID: 1},
+			{ID: 12, Label: "isolated"},
+		},
+		IPNets:           map[int][]IPNet{10: {{ID: 100, NetworkAddress: "10.0.0.0", NetworkMask: 24}}},
+		IPRanges:         map[int][]IPRange{100: {{ID: 1000, StartAddress: "10.0.0.10", EndAddress: "10.0.0.20"}}},
+		HypervisorGroups: []HypervisorGroup{{ID: 2, Label: "group"}},
+		Hypervisors: []Hypervisor{
+			{ID: 3, Label: "hv1", HypervisorGroupID: 2},
+			{ID: 4, Label: "hv2"},
+		},
+		NetworkJoins: []NetworkJoin{
+			{ID: 20, NetworkID: 10, TargetJoinType: JoinTargetHypervisorGroup, TargetJoinID: 2, Interface: "eth1"},
+			{ID: 21, NetworkID: 11, TargetJoinType: JoinTargetHypervisor, TargetJoinID: 3, Interface: "eth2"},
+			{ID: 22, NetworkID: 12, TargetJoinType: JoinTargetHypervisor, TargetJoinID: 4, Interface: "eth1"},
+		},
+		VirtualMachines: []VirtualMachine{
+			{ID: 30, Label: "vm1", HypervisorID: 3},
+			{ID: 31, Label: "vm2", HypervisorID: 4},
+		},
+		NetworkInterfaces: []NetworkInterface{
+			{ID: 40, VirtualMachineID: 30, NetworkJoinID: 20},
+			{ID: 41, VirtualMachineID: 30, NetworkJoinID: 21},
+			{ID: 42, VirtualMachineID: 31, NetworkJoinID: 22},
+		},
+	})
+}
+
+func topologyObjectIDs(nodes []TopologyNode) []int {
+	var res []int
+	for _, v := range nodes {
+		res = append(res, v.ObjectID)
+	}
+
+	return res
+}
+
+func TestNetworkTopology_Queries(t *testing.T) {
+	g := testNetworkTopology()
+
+	require.Equal(t, []int{10, 11}, topologyObjectIDs(g.HypervisorNetworks(3)))
+	require.Equal(t, []int{12}, topologyObjectIDs(g.VirtualMachineNetworks(31)))
+	require.Equal(t, []int{30}, topologyObjectIDs(g.NetworkVirtualMachines(10)))
+	require.Empty(t, g.NetworkVirtualMachines(99))
+	require.Nil(t, g.VirtualMachineNetworks(99))
+}
+
+func TestNetworkTopology_Export(t *testing.T) {
+	g := testNetworkTopology()
+
+	data, err := json.Marshal(g)
+	require.NoError(t, err)
+
+	var got networkTopologyJSON
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got.Nodes, len(g.Nodes()))
+	require.Len(t, got.Edges, len(g.Edges()))
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDOT(&buf))
+	require.Contains(t, buf.String(), `"Network:10" -> "HypervisorGroup:2" [label="joined eth1"];`)
+	require.Contains(t, buf.String(), `"NetworkInterface:40" -> "Network:10" [label="uses eth1"];`)
+}
+
+func TestNetworks_Topology(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// every list must be read page by page
+	paged := func(root string, items ...interface{}) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			require.NotEmpty(t, r.URL.Query().Get("per_page"), r.URL.Path)
+			writeTestPage(t, w, r, root, items)
+		}
+	}
+
+	var networks []interface{}
+	for i := 1; i <= 120; i++ {
+		networks = append(networks, Network{ID: i, NetworkGroupID: 1})
+	}
+
+	var hypervisors []interface{}
+	for i := 1; i <= 120; i++ {
+		hypervisors = append(hypervisors, Hypervisor{ID: i, HypervisorGroupID: 2})
+	}
+
+	mux.HandleFunc("/settings/network_zones.json", paged("network_group", NetworkGroup{ID: 1}))
+	mux.HandleFunc("/settings/hypervisor_zones.json", paged("hypervisor_group", HypervisorGroup{ID: 2}))
+	mux.HandleFunc("/settings/hypervisors.json", paged("hypervisor", hypervisors...))
+	mux.HandleFunc("/settings/networks.json", paged("network", networks...))
+	mux.HandleFunc("/settings/hypervisor_zones/2/network_joins.json", paged("networking_network_join", NetworkJoin{ID: 50, NetworkID: 120, Interface: "eth1"}))
+	mux.HandleFunc("/settings/hypervisors/", paged("networking_network_join"))
+
+	mux.HandleFunc("/settings/networks/", func(w http.ResponseWriter, r *http.Request) {
+		var networkID, ipNetID int
+		if n, _ := fmt.Sscanf(r.URL.Path, "/settings/networks/%d/ip_nets/%d/ip_ranges.json", &networkID, &ipNetID); n == 2 {
+			paged("ip_range", IPRange{ID: 1000 + ipNetID})(w, r)
+			return
+		}
+
+		fmt.Sscanf(r.URL.Path, "/settings/networks/%d/ip_nets.json", &networkID)
+		paged("ip_net", IPNet{ID: 100 + networkID})(w, r)
+	})
+
+	var items []interface{}
+	for i := 1; i <= 150; i++ {
+		items = append(items, VirtualMachine{ID: i})
+	}
+
+	mux.HandleFunc("/virtual_machines.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "virtual_machine", items)
+	})
+
+	mux.HandleFunc("/virtual_machines/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/virtual_machines/%d/network_interfaces.json", &id)
+		paged("network_interface", NetworkInterface{ID: 1000 + id, NetworkJoinID: 50})(w, r)
+	})
+
+	g, _, err := client.Networks.Topology(ctx)
+	require.NoError(t, err)
+
+	for kind, id := range map[string]int{
+		TopologyNetworkGroup:     1,
+		TopologyNetwork:          120,
+		TopologyIPNet:            220,
+		TopologyIPRange:          1220,
+		TopologyHypervisorGroup:  2,
+		TopologyHypervisor:       120,
+		TopologyVirtualMachine:   150,
+		TopologyNetworkInterface: 1150,
+	} {
+		_, ok := g.Node(kind, id)
+		require.True(t, ok, "%s %d", kind, id)
+	}
+
+	require.Len(t, g.NetworkVirtualMachines(120), 150)
+}
@@ -73,6 +73,7 @@ type Client struct {
 	InstancePackages          InstancePackagesService
 	IntegratedDataStores      IntegratedDataStoresService
 	IPAddresses               IPAddressesService
+	IPAM                      IPAMService
 	IPNets                    IPNetsService
 	IPRanges                  IPRangesService
 	Licenses                  LicensesService
@@ -85,6 +86,7 @@ type Client struct {
 	RemoteTemplates           RemoteTemplatesService
 	Resolvers                 ResolversService
 	Roles                     RolesService
+	Schedules                 SchedulesService
 	SoftwareLicenses          SoftwareLicensesService
 	SSHKeys                   SSHKeysService
 	Transactions              TransactionsService
@@ -204,6 +206,7 @@ func NewClient(httpClient *http.Client) *Client {
 	c.InstancePackages = &InstancePackagesServiceOp{client: c}
 	c.IntegratedDataStores = &IntegratedDataStoresServiceOp{client: c}
 	c.IPAddresses = &IPAddressesServiceOp{client: c}
+	c.IPAM = &IPAMServiceOp{client: c}
 	c.IPNets = &IPNetsServiceOp{client: c}
 	c.IPRanges = &IPRangesServiceOp{client: c}
 	c.Licenses = &LicensesServiceOp{client: c}
@@ -216,6 +219,7 @@ func NewClient(httpClient *http.Client) *Client {
 	c.RemoteTemplates = &RemoteTemplatesServiceOp{client: c}
 	c.Resolvers = &ResolversServiceOp{client: c}
 	c.Roles = &RolesServiceOp{client: c}
+	c.Schedules = &SchedulesServiceOp{client: c}
 	c.SoftwareLicenses = &SoftwareLicensesServiceOp{client: c}
 	c.SSHKeys = &SSHKeysServiceOp{client: c}
 	c.Transactions = &TransactionsServiceOp{client: c}
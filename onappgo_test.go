@@ -106,7 +106,9 @@ func testClientServices(t *testing.T, c *Client) {
 		"Configurations",
 		"UserGroups",
 		"FirewallRules",
-		"UserWhiteList",
+		"UserWhiteLists",
+		"Schedules",
+		"IPAM",
 	}
 
 	cp := reflect.ValueOf(c)
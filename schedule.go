@@ -0,0 +1,404 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const schedulesBasePath string = "schedules"
+
+var scheduleTargetPaths = map[string]string{
+	ScheduleTargetDisk:           "settings/disks/%d/schedules",
+	ScheduleTargetVirtualMachine: "virtual_machines/%d/schedules",
+}
+
+const (
+	// ScheduleTargetDisk is a schedule of the disk backups
+	ScheduleTargetDisk = "Disk"
+
+	// ScheduleTargetVirtualMachine is a schedule of the VirtualMachine (incremental) backups
+	ScheduleTargetVirtualMachine = "VirtualMachine"
+
+	// ScheduleEnabled is a status of enabled schedule
+	ScheduleEnabled = "enabled"
+
+	// ScheduleDisabled is a status of disabled schedule
+	ScheduleDisabled = "disabled"
+)
+
+// Schedule periods
+const (
+	ScheduleDays   = "days"
+	ScheduleWeeks  = "weeks"
+	ScheduleMonths = "months"
+	ScheduleYears  = "years"
+)
+
+// SchedulesService is an interface for interfacing with the Schedule
+// endpoints of the OnApp API
+// https://docs.onapp.com/apim/latest/schedules
+type SchedulesService interface {
+	List(context.Context, *ListOptions) ([]Schedule, *Response, error)
+	ListByTarget(context.Context, string, int, *ListOptions) ([]Schedule, *Response, error)
+	Get(context.Context, int) (*Schedule, *Response, error)
+	Create(context.Context, *ScheduleCreateRequest) (*Schedule, *Response, error)
+	Delete(context.Context, int, interface{}) (*Response, error)
+	Edit(context.Context, int, *ScheduleEditRequest) (*Response, error)
+}
+
+// SchedulesServiceOp handles communication with the Schedule related methods of the
+// OnApp API.
+type SchedulesServiceOp struct {
+	client *Client
+}
+
+var _ SchedulesService = &SchedulesServiceOp{}
+
+// Schedule represent schedule of the automatic backups
+type Schedule struct {
+	Action         string `json:"action,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	Duration       int    `json:"duration,omitempty"`
+	FailureCount   int    `json:"failure_count,omitempty"`
+	ID             int    `json:"id,omitempty"`
+	Period         string `json:"period,omitempty"`
+	RotationPeriod int    `json:"rotation_period,omitempty"`
+	StartAt        string `json:"start_at,omitempty"`
+	Status         string `json:"status,omitempty"`
+	TargetID       int    `json:"target_id,omitempty"`
+	TargetType     string `json:"target_type,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+	UserID         int    `json:"user_id,omitempty"`
+}
+
+// ScheduleCreateRequest represents a request to create a Schedule
+type ScheduleCreateRequest struct {
+	Action         string `json:"action,omitempty"`
+	Duration       int    `json:"duration,omitempty"`
+	Period         string `json:"period,omitempty"`
+	RotationPeriod int    `json:"rotation_period,omitempty"`
+	StartAt        string `json:"start_at,omitempty"`
+	Status         string `json:"status,omitempty"`
+
+	// ScheduleTargetDisk or ScheduleTargetVirtualMachine
+	TargetType string `json:"-"`
+	TargetID   int    `json:"-"`
+}
+
+// ScheduleEditRequest represents a request to edit a Schedule
+type ScheduleEditRequest struct {
+	Duration       int    `json:"duration,omitempty"`
+	Period         string `json:"period,omitempty"`
+	RotationPeriod int    `json:"rotation_period,omitempty"`
+	StartAt        string `json:"start_at,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+type scheduleCreateRequestRoot struct {
+	ScheduleCreateRequest *ScheduleCreateRequest `json:"schedule"`
+}
+
+type scheduleEditRequestRoot struct {
+	ScheduleEditRequest *ScheduleEditRequest `json:"schedule"`
+}
+
+type scheduleRoot struct {
+	Schedule *Schedule `json:"schedule"`
+}
+
+func (d ScheduleCreateRequest) String() string {
+	return godo.Stringify(d)
+}
+
+// List all Schedules
+func (s *SchedulesServiceOp) List(ctx context.Context, opt *ListOptions) ([]Schedule, *Response, error) {
+	return s.list(ctx, schedulesBasePath+apiFormat, opt)
+}
+
+// ListByTarget list Schedules of the Disk or VirtualMachine
+func (s *SchedulesServiceOp) ListByTarget(ctx context.Context, targetType string, targetID int, opt *ListOptions) ([]Schedule, *Response, error) {
+	if targetID < 1 {
+		return nil, nil, godo.NewArgError("targetID", "cannot be less than 1")
+	}
+
+	val, ok := scheduleTargetPaths[targetType]
+	if !ok {
+		return nil, nil, godo.NewArgError("Schedule ListByTarget: wrong targetType", targetType)
+	}
+
+	return s.list(ctx, fmt.Sprintf(val, targetID)+apiFormat, opt)
+}
+
+func (s *SchedulesServiceOp) list(ctx context.Context, path string, opt *ListOptions) ([]Schedule, *Response, error) {
+	path, err := addOptions(path, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []map[string]Schedule
+	resp, err := s.client.Do(ctx, req, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	arr := make([]Schedule, len(out))
+	for i := range arr {
+		arr[i] = out[i]["schedule"]
+	}
+
+	return arr, resp, err
+}
+
+// Get individual Schedule
+func (s *SchedulesServiceOp) Get(ctx context.Context, id int) (*Schedule, *Response, error) {
+	if id < 1 {
+		return nil, nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf("%s/%d%s", schedulesBasePath, id, apiFormat)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(scheduleRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Schedule, resp, err
+}
+
+// Create Schedule for the Disk or VirtualMachine
+func (s *SchedulesServiceOp) Create(ctx context.Context, createRequest *ScheduleCreateRequest) (*Schedule, *Response, error) {
+	if createRequest == nil {
+		return nil, nil, godo.NewArgError("Schedule createRequest", "cannot be nil")
+	}
+
+	if createRequest.TargetID < 1 {
+		return nil, nil, godo.NewArgError("TargetID", "cannot be less than 1")
+	}
+
+	val, ok := scheduleTargetPaths[createRequest.TargetType]
+	if !ok {
+		return nil, nil, godo.NewArgError("Schedule Create: wrong TargetType", createRequest.TargetType)
+	}
+
+	if err := validateSchedule(createRequest.Duration, createRequest.Period, createRequest.StartAt); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(val, createRequest.TargetID) + apiFormat
+	rootRequest := &scheduleCreateRequestRoot{
+		ScheduleCreateRequest: createRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, rootRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Println("Schedule [Create] req: ", req)
+
+	root := new(scheduleRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Schedule, resp, err
+}
+
+// Delete Schedule
+func (s *SchedulesServiceOp) Delete(ctx context.Context, id int, meta interface{}) (*Response, error) {
+	if id < 1 {
+		return nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	path := fmt.Sprintf("%s/%d%s", schedulesBasePath, id, apiFormat)
+	path, err := addOptions(path, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Schedule [Delete] req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Edit Schedule
+func (s *SchedulesServiceOp) Edit(ctx context.Context, id int, editRequest *ScheduleEditRequest) (*Response, error) {
+	if id < 1 {
+		return nil, godo.NewArgError("id", "cannot be less than 1")
+	}
+
+	if editRequest == nil {
+		return nil, godo.NewArgError("Schedule [Edit] editRequest", "cannot be nil")
+	}
+
+	if editRequest.Period != "" {
+		if err := validateSchedule(1, editRequest.Period, editRequest.StartAt); err != nil {
+			return nil, err
+		}
+	}
+
+	path := fmt.Sprintf("%s/%d%s", schedulesBasePath, id, apiFormat)
+	rootRequest := &scheduleEditRequestRoot{
+		ScheduleEditRequest: editRequest,
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, rootRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Schedule [Edit]  req: ", req)
+
+	return s.client.Do(ctx, req, nil)
+}
+
+func validateSchedule(duration int, period string, startAt string) error {
+	if duration < 1 {
+		return godo.NewArgError("Duration", "cannot be less than 1")
+	}
+
+	if !StringInSlice([]string{ScheduleDays, ScheduleWeeks, ScheduleMonths, ScheduleYears}, period, false) {
+		return godo.NewArgError("Period", fmt.Sprintf("must be one of days, weeks, months, years but got '%s'", period))
+	}
+
+	if startAt != "" {
+		if _, err := parseScheduleTime(startAt); err != nil {
+			return godo.NewArgError("StartAt", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// scheduleTimeLayouts are the formats of time used by OnApp for start_at
+var scheduleTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05.000-07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+func parseScheduleTime(str string) (time.Time, error) {
+	for _, layout := range scheduleTimeLayouts {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown time format '%s'", str)
+}
+
+// Enabled check if schedule status is 'enabled'
+func (obj Schedule) Enabled() bool {
+	return obj.Status == ScheduleEnabled
+}
+
+// NextRuns compute up to n run times of the Schedule which are not before
+// from. Disabled schedule has no runs.
+func (obj Schedule) NextRuns(from time.Time, n int) ([]time.Time, error) {
+	if n < 1 || !obj.Enabled() {
+		return nil, nil
+	}
+
+	if err := validateSchedule(obj.Duration, obj.Period, obj.StartAt); err != nil {
+		return nil, err
+	}
+
+	start := from
+	if obj.StartAt != "" {
+		start, _ = parseScheduleTime(obj.StartAt)
+	}
+
+	// skip the runs in the past without walking through every of them
+	k := 0
+	if from.After(start) {
+		k = obj.approxRunsBetween(start, from)
+	}
+
+	res := make([]time.Time, 0, n)
+	for ; len(res) < n; k++ {
+		t := obj.runAt(start, k)
+		if t.Before(from) {
+			continue
+		}
+
+		res = append(res, t)
+	}
+
+	return res, nil
+}
+
+// runAt return time of k-th run, every run is counted from the start to
+// avoid drift of the month days
+func (obj Schedule) runAt(start time.Time, k int) time.Time {
+	step := k * obj.Duration
+
+	switch obj.Period {
+	case ScheduleWeeks:
+		return start.AddDate(0, 0, 7*step)
+	case ScheduleMonths:
+		return addMonths(start, step)
+	case ScheduleYears:
+		return addMonths(start, 12*step)
+	}
+
+	return start.AddDate(0, 0, step)
+}
+
+// addMonths add months to the time, day is clamped to the last day of the
+// target month instead of rolling over to the next one
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	day := t.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
+
+// approxRunsBetween return number of runs between start and from, never
+// greater than exact one
+func (obj Schedule) approxRunsBetween(start, from time.Time) int {
+	var units int
+
+	switch obj.Period {
+	case ScheduleWeeks:
+		units = int(from.Sub(start).Hours() / (24 * 7))
+	case ScheduleMonths:
+		units = (from.Year()-start.Year())*12 + int(from.Month()) - int(start.Month()) - 1
+	case ScheduleYears:
+		units = from.Year() - start.Year() - 1
+	default:
+		units = int(from.Sub(start).Hours() / 24)
+	}
+
+	// daylight saving time can shift calendar days a bit
+	k := units/obj.Duration - 1
+	if k < 0 {
+		return 0
+	}
+
+	return k
+}
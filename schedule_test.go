@@ -0,0 +1,235 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_NextRuns(t *testing.T) {
+	from := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     []string
+	}{
+		{
+			name:     "daily",
+			schedule: Schedule{Duration: 1, Period: ScheduleDays, StartAt: "2020-01-01T03:00:00Z", Status: ScheduleEnabled},
+			want:     []string{"2021-03-11T03:00:00Z", "2021-03-12T03:00:00Z", "2021-03-13T03:00:00Z"},
+		},
+		{
+			name:     "every 2 weeks",
+			schedule: Schedule{Duration: 2, Period: ScheduleWeeks, StartAt: "2021-03-01T00:00:00Z", Status: ScheduleEnabled},
+			want:     []string{"2021-03-15T00:00:00Z", "2021-03-29T00:00:00Z", "2021-04-12T00:00:00Z"},
+		},
+		{
+			name:     "monthly from the last day of month",
+			schedule: Schedule{Duration: 1, Period: ScheduleMonths, StartAt: "2020-01-31T00:00:00Z", Status: ScheduleEnabled},
+			want:     []string{"2021-03-31T00:00:00Z", "2021-04-30T00:00:00Z", "2021-05-31T00:00:00Z"},
+		},
+		{
+			name:     "yearly from the leap day",
+			schedule: Schedule{Duration: 1, Period: ScheduleYears, StartAt: "2020-02-29T00:00:00Z", Status: ScheduleEnabled},
+			want:     []string{"2022-02-28T00:00:00Z", "2023-02-28T00:00:00Z", "2024-02-29T00:00:00Z"},
+		},
+		{
+			name:     "yearly starts in the future",
+			schedule: Schedule{Duration: 1, Period: ScheduleYears, StartAt: "2022-06-01T00:00:00Z", Status: ScheduleEnabled},
+			want:     []string{"2022-06-01T00:00:00Z", "2023-06-01T00:00:00Z", "2024-06-01T00:00:00Z"},
+		},
+		{
+			name:     "disabled",
+			schedule: Schedule{Duration: 1, Period: ScheduleDays, StartAt: "2020-01-01T03:00:00Z", Status: ScheduleDisabled},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.schedule.NextRuns(from, 3)
+			require.NoError(t, err)
+
+			var str []string
+			for _, v := range got {
+				str = append(str, v.Format(time.RFC3339))
+			}
+			require.Equal(t, tt.want, str)
+		})
+	}
+}
+
+func TestSchedule_NextRunsWrongPeriod(t *testing.T) {
+	s := Schedule{Duration: 1, Period: "hours", Status: ScheduleEnabled}
+
+	_, err := s.NextRuns(time.Now(), 1)
+	require.Error(t, err)
+}
+
+func TestSchedules_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/schedules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[{"schedule":{"id":1,"target_type":"Disk","target_id":5}},{"schedule":{"id":2,"target_type":"VirtualMachine","target_id":7}}]`)
+	})
+
+	mux.HandleFunc("/virtual_machines/7/schedules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[{"schedule":{"id":2,"target_type":"VirtualMachine","target_id":7}}]`)
+	})
+
+	schedules, _, err := client.Schedules.List(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []Schedule{
+		{ID: 1, TargetType: ScheduleTargetDisk, TargetID: 5},
+		{ID: 2, TargetType: ScheduleTargetVirtualMachine, TargetID: 7},
+	}, schedules)
+
+	schedules, _, err = client.Schedules.ListByTarget(ctx, ScheduleTargetVirtualMachine, 7, nil)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, 2, schedules[0].ID)
+
+	_, _, err = client.Schedules.ListByTarget(ctx, "Hypervisor", 7, nil)
+	require.Error(t, err)
+
+	_, _, err = client.Schedules.ListByTarget(ctx, ScheduleTargetDisk, 0, nil)
+	require.Error(t, err)
+}
+
+func TestSchedules_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/schedules/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"schedule":{"id":1,"action":"autobackup","duration":1,"period":"days","status":"enabled"}}`)
+	})
+
+	schedule, _, err := client.Schedules.Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, &Schedule{ID: 1, Action: "autobackup", Duration: 1, Period: ScheduleDays, Status: ScheduleEnabled}, schedule)
+	require.True(t, schedule.Enabled())
+
+	_, _, err = client.Schedules.Get(ctx, 0)
+	require.Error(t, err)
+}
+
+func TestSchedules_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/disks/5/schedules.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		var got map[string]map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		require.Equal(t, map[string]interface{}{
+			"action":          "autobackup",
+			"duration":        float64(2),
+			"period":          "weeks",
+			"rotation_period": float64(4),
+			"start_at":        "2020-01-31T10:00:00Z",
+		}, got["schedule"])
+
+		fmt.Fprint(w, `{"schedule":{"id":3,"target_type":"Disk","target_id":5,"duration":2,"period":"weeks"}}`)
+	})
+
+	createRequest := &ScheduleCreateRequest{
+		Action:         "autobackup",
+		Duration:       2,
+		Period:         ScheduleWeeks,
+		RotationPeriod: 4,
+		StartAt:        "2020-01-31T10:00:00Z",
+		TargetType:     ScheduleTargetDisk,
+		TargetID:       5,
+	}
+
+	schedule, _, err := client.Schedules.Create(ctx, createRequest)
+	require.NoError(t, err)
+	require.Equal(t, 3, schedule.ID)
+
+	tests := []struct {
+		name string
+		edit func(*ScheduleCreateRequest)
+	}{
+		{"wrong target type", func(r *ScheduleCreateRequest) { r.TargetType = "Hypervisor" }},
+		{"no target", func(r *ScheduleCreateRequest) { r.TargetID = 0 }},
+		{"no duration", func(r *ScheduleCreateRequest) { r.Duration = 0 }},
+		{"wrong period", func(r *ScheduleCreateRequest) { r.Period = "hours" }},
+		{"wrong start", func(r *ScheduleCreateRequest) { r.StartAt = "tomorrow" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := *createRequest
+			tt.edit(&req)
+
+			_, _, err := client.Schedules.Create(ctx, &req)
+			require.Error(t, err)
+		})
+	}
+
+	_, _, err = client.Schedules.Create(ctx, nil)
+	require.Error(t, err)
+}
+
+func TestSchedules_Edit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var bodies []string
+	mux.HandleFunc("/schedules/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		body, _ := json.Marshal(got)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := client.Schedules.Edit(ctx, 1, &ScheduleEditRequest{Status: ScheduleDisabled})
+	require.NoError(t, err)
+
+	_, err = client.Schedules.Edit(ctx, 1, &ScheduleEditRequest{Period: ScheduleMonths, StartAt: "2020-01-31 10:00"})
+	require.NoError(t, err)
+
+	_, err = client.Schedules.Edit(ctx, 1, &ScheduleEditRequest{Period: "hours"})
+	require.Error(t, err)
+
+	_, err = client.Schedules.Edit(ctx, 1, nil)
+	require.Error(t, err)
+
+	_, err = client.Schedules.Edit(ctx, 0, &ScheduleEditRequest{})
+	require.Error(t, err)
+
+	require.Len(t, bodies, 2)
+	require.JSONEq(t, `{"schedule":{"status":"disabled"}}`, bodies[0])
+	require.JSONEq(t, `{"schedule":{"period":"months","start_at":"2020-01-31 10:00"}}`, bodies[1])
+}
+
+func TestSchedules_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deleted := false
+	mux.HandleFunc("/schedules/1.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := client.Schedules.Delete(ctx, 1, nil)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	_, err = client.Schedules.Delete(ctx, 0, nil)
+	require.Error(t, err)
+}
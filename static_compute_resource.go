@@ -32,7 +32,16 @@ type HypervisorsService interface {
 
 	Reboot(context.Context, int, *HypervisorRebootRequest) (*Response, error)
 
+	EnableMaintenanceMode(context.Context, int) (*Response, error)
+	DisableMaintenanceMode(context.Context, int) (*Response, error)
+	Drain(context.Context, int, *HypervisorDrainOptions) (*HypervisorDrainReport, *Response, error)
+	Undrain(context.Context, int) (*Response, error)
+
+	Statistics(context.Context, int, *HypervisorStatisticsRequest) ([]HypervisorStatistic, *Response, error)
+
 	Refresh(context.Context, int) (*HardwareDevices, *Response, error)
+	HardwareDevices(context.Context, int) (*HardwareDevices, *Response, error)
+	Inventory(context.Context, *HardwareInventoryOptions) (*HardwareInventory, *Response, error)
 	Attach(context.Context, int, map[string]interface{}) (*Response, error)
 
 	GetIntegratedStorageSettings(context.Context, int) (*IntegratedStorageSettings, *Response, error)
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/digitalocean/godo"
 )
@@ -26,6 +28,8 @@ const (
 
 	// TransactionFailed is a failed transaction status
 	TransactionFailed = "failed"
+
+	defaultTransactionWaitInterval = 10 * time.Second
 )
 
 // TransactionsService handles communction with action related methods of the
@@ -36,6 +40,8 @@ type TransactionsService interface {
 
 	GetByFilter(context.Context, interface{}, *ListOptions) (*Transaction, *Response, error)
 	ListByGroup(context.Context, interface{}, bool, *ListOptions) ([]Transaction, *Response, error)
+
+	Wait(context.Context, []Transaction, *TransactionWaitOptions) ([]Transaction, *Response, error)
 }
 
 // TransactionsServiceOp handles communition with the image action related methods of the
@@ -72,6 +78,15 @@ type Transaction struct {
 	Params                 map[string]interface{} `json:"params,omitempty"`
 }
 
+// TransactionWaitOptions -
+type TransactionWaitOptions struct {
+	// Delay between polls, 10 seconds if zero
+	Interval time.Duration
+
+	// Progress is called after every poll with the current state of transactions
+	Progress func([]Transaction)
+}
+
 type transactionRoot struct {
 	Transaction *Transaction `json:"transaction"`
 }
@@ -274,6 +289,66 @@ func (trx *Transaction) equal(filter interface{}) bool {
 	return true
 }
 
+// Wait poll transactions until all of them are finished. Error is returned
+// if any transaction is failed or cancelled.
+func (s *TransactionsServiceOp) Wait(ctx context.Context, trxs []Transaction, opts *TransactionWaitOptions) ([]Transaction, *Response, error) {
+	if opts == nil {
+		opts = &TransactionWaitOptions{}
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultTransactionWaitInterval
+	}
+
+	res := make([]Transaction, len(trxs))
+	copy(res, trxs)
+
+	var resp *Response
+	for {
+		incomplete := false
+		for i := range res {
+			if res[i].Finished() {
+				continue
+			}
+
+			trx, r, err := s.Get(ctx, res[i].ID)
+			if err != nil {
+				return res, r, err
+			}
+			resp = r
+
+			res[i] = *trx
+			if !trx.Finished() {
+				incomplete = true
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+
+		if !incomplete {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return res, resp, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	for _, trx := range res {
+		if trx.Unlucky() {
+			return res, resp, fmt.Errorf("Transaction [%d] %s of %s [%d] is %s",
+				trx.ID, trx.Action, trx.AssociatedObjectType, trx.AssociatedObjectID, trx.Status)
+		}
+	}
+
+	return res, resp, nil
+}
+
 func lastTransaction(ctx context.Context, client *Client, filter interface{}) (*Transaction, *Response, error) {
 	opt := &ListOptions{
 		PerPage: searchTransactions,
@@ -287,6 +362,101 @@ func lastTransaction(ctx context.Context, client *Client, filter interface{}) (*
 	return &lst[0], resp, err
 }
 
+// serverTime return time of the server before the request which got the
+// response was sent, start is local time before sending the request. Date
+// header has a second resolution, so a second is subtracted.
+func serverTime(resp *Response, start time.Time) time.Time {
+	if resp == nil || resp.Response == nil {
+		return start
+	}
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return start
+	}
+
+	return date.Add(-time.Since(start) - time.Second)
+}
+
+// transactionSince return the newest transaction of the object with one of
+// the actions created not before since, error if there is no such transaction
+func transactionSince(ctx context.Context, client *Client, objectType string, objectID int, since time.Time, actions ...string) (*Transaction, *Response, error) {
+	opt := &ListOptions{
+		PerPage: searchTransactions,
+	}
+
+	lst, resp, err := client.Transactions.List(ctx, opt)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var res *Transaction
+	for i := range lst {
+		trx := &lst[i]
+		if trx.AssociatedObjectType != objectType || trx.AssociatedObjectID != objectID {
+			continue
+		}
+
+		if len(actions) > 0 && !containsString(actions, trx.Action) {
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, trx.CreatedAt)
+		if err != nil || createdAt.Before(since) {
+			continue
+		}
+
+		if res == nil || trx.ID > res.ID {
+			res = trx
+		}
+	}
+
+	if res == nil {
+		return nil, resp, fmt.Errorf("transaction %v of %s [%d] created after %s not found",
+			actions, objectType, objectID, since.Format(time.RFC3339))
+	}
+
+	return res, resp, nil
+}
+
+// transactionChain return transactions of the chain of the root transaction
+// ordered by ID, only the root if it does not belong to a chain
+func transactionChain(ctx context.Context, client *Client, root *Transaction) ([]Transaction, *Response, error) {
+	if root.ChainID == 0 {
+		return []Transaction{*root}, nil, nil
+	}
+
+	opt := &ListOptions{
+		PerPage: searchTransactions,
+	}
+
+	lst, resp, err := client.Transactions.List(ctx, opt)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	res := []Transaction{*root}
+	for _, v := range lst {
+		if v.ChainID == root.ChainID && v.ID != root.ID {
+			res = append(res, v)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, resp, nil
+}
+
+func containsString(lst []string, value string) bool {
+	for _, v := range lst {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (trx Transaction) String() string {
 	return godo.Stringify(trx)
 }
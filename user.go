@@ -218,22 +218,53 @@ type UserDeleteRequest struct {
 	Force int `json:"force,omitempty"` // json body request
 }
 
-// Delete User.
+// UserDeleteOptions -
+type UserDeleteOptions struct {
+	// Release all IPAddresses owned by the User before deletion
+	ReleaseIPAddresses bool `url:"-"`
+
+	// Extra parameters of the delete request, encoded as meta of Delete
+	Params interface{} `url:"-"`
+}
+
+// Delete User. Meta is *UserDeleteOptions, *UserDeleteRequest sent as the
+// body or query parameters, forced deletion is requested if there is no
+// *UserDeleteRequest.
 func (s *UsersServiceOp) Delete(ctx context.Context, id int, meta interface{}) (*Response, error) {
 	if id < 1 {
 		return nil, godo.NewArgError("id", "cannot be less than 1")
 	}
 
-	path := fmt.Sprintf("%s/%d%s", usersBasePath, id, apiFormat)
-	path, err := addOptions(path, meta)
-	if err != nil {
-		return nil, err
+	if opts, ok := meta.(*UserDeleteOptions); ok {
+		meta = nil
+		if opts != nil {
+			meta = opts.Params
+		}
+
+		if opts != nil && opts.ReleaseIPAddresses {
+			resp, err := s.releaseIPAddresses(ctx, id)
+			if err != nil {
+				return resp, err
+			}
+		}
 	}
 
 	// Forced user delete, must be moved out to the user space
 	opts := &UserDeleteRequest{
 		Force: 1,
 	}
+	if deleteRequest, ok := meta.(*UserDeleteRequest); ok {
+		meta = nil
+		if deleteRequest != nil {
+			opts = deleteRequest
+		}
+	}
+
+	path := fmt.Sprintf("%s/%d%s", usersBasePath, id, apiFormat)
+	path, err := addOptions(path, meta)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, opts)
 	if err != nil {
@@ -244,6 +275,30 @@ func (s *UsersServiceOp) Delete(ctx context.Context, id int, meta interface{}) (
 	return s.client.Do(ctx, req, nil)
 }
 
+// releaseIPAddresses unassign all IPAddresses owned by the User
+func (s *UsersServiceOp) releaseIPAddresses(ctx context.Context, id int) (*Response, error) {
+	lst, resp, err := s.client.IPAddresses.ListUserOwned(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, v := range lst {
+		unassignRequest := &AssignIPAddress{
+			Address:   v.Address,
+			IPNetID:   v.IPNetID,
+			IPRangeID: v.IPRangeID,
+			UserID:    id,
+		}
+
+		resp, err = s.client.IPAddresses.UnassignUser(ctx, v.NetworkID, unassignRequest)
+		if err != nil {
+			return resp, fmt.Errorf("release of IPAddress %s owned by User [%d] failed: %s", v.Address, id, err)
+		}
+	}
+
+	return resp, nil
+}
+
 // Edit User
 func (s *UsersServiceOp) Edit(ctx context.Context, id int, editRequest *UserEditRequest) (*Response, error) {
 	if id < 1 {
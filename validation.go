@@ -0,0 +1,52 @@
+package onappgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError reports errors of the request found before it is sent to
+// the OnApp API. Errors are keyed by the request fields in the same way as
+// ErrorResponse.Errors.
+type ValidationError struct {
+	Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// Add error message for the field
+func (e *ValidationError) Add(field string, format string, args ...interface{}) {
+	if e.Errors == nil {
+		e.Errors = make(map[string][]string)
+	}
+
+	e.Errors[field] = append(e.Errors[field], fmt.Sprintf(format, args...))
+}
+
+// Has check if there are errors for the field
+func (e *ValidationError) Has(field string) bool {
+	return len(e.Errors[field]) > 0
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	var lst []string
+	for _, name := range fields {
+		lst = append(lst, fmt.Sprintf("%s %s", name, strings.Join(e.Errors[name], ", ")))
+	}
+
+	return "validation failed: " + strings.Join(lst, "; ")
+}
+
+// errOrNil return nil if there are no errors, so result can be returned as error
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -19,6 +19,8 @@ type VirtualMachinesService interface {
 	Get(context.Context, int) (*VirtualMachine, *Response, error)
 	Create(context.Context, *VirtualMachineCreateRequest) (*VirtualMachine, *Response, error)
 	Delete(context.Context, int, interface{}) (*Transaction, *Response, error)
+	Select(context.Context, *VirtualMachineSelector) ([]VirtualMachine, *Response, error)
+	Console(context.Context, int) (*Console, *Response, error)
 	// Edit(context.Context, int, *ListOptions) ([]VirtualMachine, *Response, error)
 
 	// TODO !!!
@@ -30,6 +30,10 @@ type VirtualMachineActionsService interface {
 	AssignIPAddress(context.Context, int, interface{}) (*Transaction, *Response, error)
 	UnAssignIPAddress(context.Context, int, int, interface{}) (*Transaction, *Response, error)
 	ListIPAddresses(context.Context, int) (*Transaction, *Response, error)
+
+	ApplyFirewallRules(context.Context, int) (*Transaction, *Response, error)
+
+	Migrate(context.Context, int, int) (*Transaction, *Response, error)
 }
 
 // VirtualMachineActionsServiceOp handles communication with the VirtualMachine action related
@@ -174,6 +178,46 @@ func (s *VirtualMachineActionsServiceOp) ListIPAddresses(ctx context.Context, id
 	return s.doAction(ctx, id, request, nil, nil)
 }
 
+// ApplyFirewallRules - Apply changed firewall rules of the VirtualMachine
+func (s *VirtualMachineActionsServiceOp) ApplyFirewallRules(ctx context.Context, id int) (*Transaction, *Response, error) {
+	request := &ActionRequest{"method": http.MethodPost, "type": "update_firewall_rules", "action": "update_firewall_rules"}
+	return s.doAction(ctx, id, request, nil, nil)
+}
+
+type virtualMachineMigrationRequest struct {
+	Destination           int `json:"destination"`
+	ColdMigrateOnRollback int `json:"cold_migrate_on_rollback"`
+}
+
+type virtualMachineMigrationRequestRoot struct {
+	VirtualMachine *virtualMachineMigrationRequest `json:"virtual_machine"`
+}
+
+// Migrate - Migrate VirtualMachine to the destination Hypervisor, running
+// VirtualMachine is migrated hot
+func (s *VirtualMachineActionsServiceOp) Migrate(ctx context.Context, id int, destinationID int) (*Transaction, *Response, error) {
+	if destinationID < 1 {
+		return nil, nil, godo.NewArgError("destinationID", "cannot be less than 1")
+	}
+
+	vm, resp, err := s.client.VirtualMachines.Get(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	action := "cold_migrate"
+	if vm.Booted {
+		action = "hot_migrate"
+	}
+
+	request := &ActionRequest{"method": http.MethodPost, "type": "migrate", "path": "migration", "action": action}
+	root := &virtualMachineMigrationRequestRoot{
+		VirtualMachine: &virtualMachineMigrationRequest{Destination: destinationID},
+	}
+
+	return s.doAction(ctx, id, request, root, nil)
+}
+
 func (s *VirtualMachineActionsServiceOp) doAction(ctx context.Context, id int,
 	request *ActionRequest, jsonParams interface{}, urlParams interface{}) (*Transaction, *Response, error) {
 	if id < 1 {
@@ -0,0 +1,181 @@
+package onappgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+const virtualMachinesByUserBasePath string = "users/%d/virtual_machines"
+const virtualMachinesByHypervisorBasePath string = "settings/hypervisors/%d/virtual_machines"
+
+// VirtualMachineSelector describes a set of VirtualMachines. Zero value fields
+// are not used for filtering, so empty selector matches every VirtualMachine.
+// The same selector can be passed to VirtualMachines.Select or used with
+// Match/Filter over already fetched VirtualMachines (bulk actions, exports).
+type VirtualMachineSelector struct {
+	HypervisorID          int
+	UserID                int
+	OperatingSystemDistro string
+	State                 string
+	Booted                *bool
+	Locked                *bool
+
+	// Label is a shell pattern, see path.Match (e.g. "web-*")
+	Label string
+
+	// LabelRegexp is applied to the Label in addition to the pattern above
+	LabelRegexp *regexp.Regexp
+}
+
+func (sel VirtualMachineSelector) String() string {
+	return godo.Stringify(sel)
+}
+
+// Validate check if selector can be used for filtering
+func (sel *VirtualMachineSelector) Validate() error {
+	if sel == nil {
+		return nil
+	}
+
+	if sel.HypervisorID < 0 {
+		return godo.NewArgError("HypervisorID", "cannot be less than 0")
+	}
+
+	if sel.UserID < 0 {
+		return godo.NewArgError("UserID", "cannot be less than 0")
+	}
+
+	if sel.Label != "" {
+		if _, err := path.Match(sel.Label, ""); err != nil {
+			return godo.NewArgError("Label", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Match check if VirtualMachine satisfies all conditions of the selector
+func (sel *VirtualMachineSelector) Match(vm *VirtualMachine) bool {
+	if vm == nil {
+		return false
+	}
+
+	if sel == nil {
+		return true
+	}
+
+	if sel.HypervisorID > 0 && vm.HypervisorID != sel.HypervisorID {
+		return false
+	}
+
+	if sel.UserID > 0 && vm.UserID != sel.UserID {
+		return false
+	}
+
+	if sel.OperatingSystemDistro != "" && !strings.EqualFold(vm.OperatingSystemDistro, sel.OperatingSystemDistro) {
+		return false
+	}
+
+	if sel.State != "" && !strings.EqualFold(vm.State, sel.State) {
+		return false
+	}
+
+	if sel.Booted != nil && vm.Booted != *sel.Booted {
+		return false
+	}
+
+	if sel.Locked != nil && vm.Locked != *sel.Locked {
+		return false
+	}
+
+	if sel.Label != "" {
+		if ok, _ := path.Match(sel.Label, vm.Label); !ok {
+			return false
+		}
+	}
+
+	if sel.LabelRegexp != nil && !sel.LabelRegexp.MatchString(vm.Label) {
+		return false
+	}
+
+	return true
+}
+
+// Filter return VirtualMachines which match the selector
+func (sel *VirtualMachineSelector) Filter(vms []VirtualMachine) []VirtualMachine {
+	res := make([]VirtualMachine, 0, len(vms))
+	for i := range vms {
+		if sel.Match(&vms[i]) {
+			res = append(res, vms[i])
+		}
+	}
+
+	return res
+}
+
+// basePath return the narrowest endpoint which OnApp can filter by itself
+func (sel *VirtualMachineSelector) basePath() string {
+	if sel == nil {
+		return virtualMachineBasePath
+	}
+
+	if sel.HypervisorID > 0 {
+		return fmt.Sprintf(virtualMachinesByHypervisorBasePath, sel.HypervisorID)
+	}
+
+	if sel.UserID > 0 {
+		return fmt.Sprintf(virtualMachinesByUserBasePath, sel.UserID)
+	}
+
+	return virtualMachineBasePath
+}
+
+// Select return all VirtualMachines which match the selector. Filtering by
+// hypervisor or user is done by OnApp, all other conditions are checked over
+// the every page of the result.
+func (s *VirtualMachinesServiceOp) Select(ctx context.Context, sel *VirtualMachineSelector) ([]VirtualMachine, *Response, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	basePath := sel.basePath() + apiFormat
+
+	var res []VirtualMachine
+	resp, err := listAllPages(func(opt *ListOptions) (int, *Response, error) {
+		path, err := addOptions(basePath, opt)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		var out []map[string]VirtualMachine
+		resp, err := s.client.Do(ctx, req, &out)
+		if err != nil {
+			return 0, resp, err
+		}
+
+		for i := range out {
+			vm := out[i]["virtual_machine"]
+			if sel.Match(&vm) {
+				res = append(res, vm)
+			}
+		}
+
+		return len(out), resp, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return res, resp, nil
+}
@@ -0,0 +1,132 @@
+package onappgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPage write page of items requested by page/per_page query with
+// pagination headers, every item is wrapped into the root
+func writeTestPage(t *testing.T, w http.ResponseWriter, r *http.Request, root string, items []interface{}) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = len(items) + 1
+	}
+
+	out := []map[string]interface{}{}
+	for i := (page - 1) * perPage; i < len(items) && i < page*perPage; i++ {
+		out = append(out, map[string]interface{}{root: items[i]})
+	}
+
+	w.Header().Set(headerPage, strconv.Itoa(page))
+	w.Header().Set(headerPerPage, strconv.Itoa(perPage))
+	w.Header().Set(headerTotal, strconv.Itoa(len(items)))
+	require.NoError(t, json.NewEncoder(w).Encode(out))
+}
+
+func TestVirtualMachineSelector_Match(t *testing.T) {
+	booted := true
+	vm := &VirtualMachine{ID: 1, Label: "web-1", HypervisorID: 2, UserID: 3, OperatingSystemDistro: "ubuntu", State: "running", Booted: true}
+
+	tests := []struct {
+		name string
+		sel  *VirtualMachineSelector
+		want bool
+	}{
+		{"nil", nil, true},
+		{"empty", &VirtualMachineSelector{}, true},
+		{"hypervisor", &VirtualMachineSelector{HypervisorID: 2}, true},
+		{"other hypervisor", &VirtualMachineSelector{HypervisorID: 5}, false},
+		{"other user", &VirtualMachineSelector{UserID: 5}, false},
+		{"distro case", &VirtualMachineSelector{OperatingSystemDistro: "Ubuntu"}, true},
+		{"state", &VirtualMachineSelector{State: "stopped"}, false},
+		{"booted", &VirtualMachineSelector{Booted: &booted}, true},
+		{"locked", &VirtualMachineSelector{Locked: &booted}, false},
+		{"label", &VirtualMachineSelector{Label: "web-*"}, true},
+		{"other label", &VirtualMachineSelector{Label: "db-*"}, false},
+		{"label regexp", &VirtualMachineSelector{LabelRegexp: regexp.MustCompile(`-\d$`)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.sel.Match(vm))
+		})
+	}
+
+	require.False(t, (&VirtualMachineSelector{}).Match(nil))
+	require.Error(t, (&VirtualMachineSelector{Label: "["}).Validate())
+
+	vms := []VirtualMachine{{ID: 1, Label: "web-1"}, {ID: 2, Label: "db-1"}}
+	require.Equal(t, vms[:1], (&VirtualMachineSelector{Label: "web-*"}).Filter(vms))
+}
+
+func TestVirtualMachines_Select(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var items []interface{}
+	for i := 1; i <= 150; i++ {
+		label := "web-" + strconv.Itoa(i)
+		if i%2 == 0 {
+			label = "db-" + strconv.Itoa(i)
+		}
+		items = append(items, VirtualMachine{ID: i, Label: label})
+	}
+
+	pages := 0
+	mux.HandleFunc("/virtual_machines.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		pages++
+		writeTestPage(t, w, r, "virtual_machine", items)
+	})
+
+	vms, _, err := client.VirtualMachines.Select(ctx, &VirtualMachineSelector{Label: "web-*"})
+	require.NoError(t, err)
+	require.Equal(t, 2, pages)
+	require.Len(t, vms, 75)
+	require.Equal(t, 149, vms[74].ID)
+
+	vms, _, err = client.VirtualMachines.Select(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, vms, 150)
+}
+
+func TestVirtualMachines_Select_Narrowing(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/settings/hypervisors/2/virtual_machines.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "virtual_machine", []interface{}{
+			VirtualMachine{ID: 1, HypervisorID: 2, UserID: 3},
+			VirtualMachine{ID: 2, HypervisorID: 2, UserID: 4},
+		})
+	})
+
+	mux.HandleFunc("/users/3/virtual_machines.json", func(w http.ResponseWriter, r *http.Request) {
+		writeTestPage(t, w, r, "virtual_machine", []interface{}{
+			VirtualMachine{ID: 1, HypervisorID: 2, UserID: 3},
+			VirtualMachine{ID: 5, HypervisorID: 6, UserID: 3},
+		})
+	})
+
+	vms, _, err := client.VirtualMachines.Select(ctx, &VirtualMachineSelector{HypervisorID: 2, UserID: 3})
+	require.NoError(t, err)
+	require.Len(t, vms, 1)
+	require.Equal(t, 1, vms[0].ID)
+
+	vms, _, err = client.VirtualMachines.Select(ctx, &VirtualMachineSelector{UserID: 3})
+	require.NoError(t, err)
+	require.Len(t, vms, 2)
+
+	_, _, err = client.VirtualMachines.Select(ctx, &VirtualMachineSelector{UserID: -1})
+	require.Error(t, err)
+}